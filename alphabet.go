@@ -0,0 +1,117 @@
+package idgen
+
+import (
+	"math"
+	"strconv"
+)
+
+// alphabet is a case-insensitive digit set for Format and Parse.
+type alphabet struct {
+	name        string    // used in Fingerprint and Config
+	digits      string    // lowercase, in value order
+	values      [256]byte // digit value per byte, 0xff if not a digit
+	bitsPerChar float64   // log2(len(digits))
+}
+
+var (
+	alphabet36        = newAlphabet("base36", "0123456789abcdefghijklmnopqrstuvwxyz")
+	alphabetVowelFree = newAlphabet("vowel-free", "0123456789bcdfghjklmnpqrstvwxyz")
+)
+
+func newAlphabet(name, digits string) *alphabet {
+	a := &alphabet{name: name, digits: digits, bitsPerChar: math.Log2(float64(len(digits)))}
+	for i := range a.values {
+		a.values[i] = 0xff
+	}
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		a.values[c] = byte(i)
+		if c >= 'a' && c <= 'z' {
+			a.values[c-'a'+'A'] = byte(i)
+		}
+	}
+	return a
+}
+
+// alphabetByName returns the alphabet stored in Config.Alphabet; "" is base36.
+func alphabetByName(name string) (*alphabet, bool) {
+	switch name {
+	case "", alphabet36.name:
+		return alphabet36, true
+	case alphabetVowelFree.name:
+		return alphabetVowelFree, true
+	}
+	return nil, false
+}
+
+func (a *alphabet) base() uint64 { return uint64(len(a.digits)) }
+
+// maxBits returns the largest bit count whose values fit in width digits.
+func (a *alphabet) maxBits(width int) uint {
+	return uint(math.Floor(float64(width)*a.bitsPerChar + 1e-12))
+}
+
+// holds reports whether width digits can represent every bits-bit value.
+func (a *alphabet) holds(width int, bits uint) bool {
+	return float64(width)*a.bitsPerChar+1e-12 >= float64(bits)
+}
+
+// pow returns base^width, reporting false if it does not fit in an int64.
+func (a *alphabet) pow(width int) (uint64, bool) {
+	n := uint64(1)
+	for i := 0; i < width; i++ {
+		if n > math.MaxInt64/a.base() {
+			return 0, false
+		}
+		n *= a.base()
+	}
+	return n, true
+}
+
+// appendUint appends the digits of v without padding.
+func (a *alphabet) appendUint(dst []byte, v uint64) []byte {
+	if a == alphabet36 {
+		return strconv.AppendUint(dst, v, 36)
+	}
+	var buf [64]byte
+	i := len(buf)
+	for {
+		i--
+		buf[i] = a.digits[v%a.base()]
+		v /= a.base()
+		if v == 0 {
+			break
+		}
+	}
+	return append(dst, buf[i:]...)
+}
+
+// decodeDigits decodes an already trimmed id in alphabet a, skipping '-'
+// separators.
+func decodeDigits[T ~string | ~[]byte](a *alphabet, s T) (uint64, error) {
+	if len(s) == 0 {
+		return 0, errEmptyID
+	}
+	base := a.base()
+	var v uint64
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '-' {
+			continue
+		}
+		d := a.values[c]
+		if d == 0xff {
+			return 0, errInvalidID
+		}
+		if v > (math.MaxUint64-uint64(d))/base {
+			return 0, errIDOverflow
+		}
+		v = v*base + uint64(d)
+		digits++
+	}
+	if digits == 0 {
+		return 0, errInvalidID
+	}
+	return v, nil
+}
@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVowelFreeAlphabet(t *testing.T) {
+	g, err := New(WithVowelFreeAlphabet())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if g.Bits() != 39 {
+		t.Fatalf("Bits() = %d, want 39 at width 8", g.Bits())
+	}
+	for raw := int64(0); raw < 5000; raw++ {
+		id := g.Format(raw)
+		if strings.ContainsAny(id, "aeiou") || len(id) != 9 {
+			t.Fatalf("Format(%d) = %q, want 8 vowel-free characters", raw, id)
+		}
+		if back, err := g.Parse(strings.ToUpper(id)); err != nil || back != raw {
+			t.Fatalf("Parse(%q) = %d, %v; want %d", id, back, err, raw)
+		}
+	}
+	if _, err := g.Parse("bcdf-ghja"); err == nil {
+		t.Fatal("expected error for a vowel")
+	}
+	id := g.Format(g.Generate())
+	if m := g.FindAll("order " + id + " shipped"); len(m) != 1 || m[0].ID != id {
+		t.Fatalf("FindAll = %+v, want %q", m, id)
+	}
+
+	plain, _ := New()
+	if g.Fingerprint() == plain.Fingerprint() {
+		t.Fatal("alphabets should have different fingerprints")
+	}
+	full, err := New(WithVowelFreeAlphabet(), WithWidth(4), WithFullWidth(), WithEpoch(time.Now()))
+	if err != nil {
+		t.Fatalf("full width: %v", err)
+	}
+	if n := full.rp.Size(); n != 31*31*31*31 {
+		t.Fatalf("full-width range = %d, want 31^4", n)
+	}
+}
+
+func TestConfigAlphabet(t *testing.T) {
+	g, _ := New(WithVowelFreeAlphabet())
+	c, err := g.Config()
+	if err != nil || c.Alphabet != "vowel-free" {
+		t.Fatalf("Config() = %+v, %v; want vowel-free alphabet", c, err)
+	}
+	g2, err := NewFromConfig(c)
+	if err != nil {
+		t.Fatalf("NewFromConfig error: %v", err)
+	}
+	if g2.Fingerprint() != g.Fingerprint() {
+		t.Fatal("round-tripped config should keep the alphabet")
+	}
+	if err := (Config{Alphabet: "base32"}).Validate(); err == nil || !strings.Contains(err.Error(), "alphabet") {
+		t.Fatalf("Validate() = %v, want alphabet error", err)
+	}
+	if err := (Config{Alphabet: "vowel-free", Bits: 41}).Validate(); err == nil {
+		t.Fatal("expected width error: 8 vowel-free characters hold only 39 bits")
+	}
+}
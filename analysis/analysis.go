@@ -0,0 +1,256 @@
+// Package analysis reports what an outsider could infer from IDs issued under
+// a given configuration: whether code order reveals issue order, how much of
+// the code space is in use, how likely unsynchronized processes are to
+// collide, and how long until the horizon.
+//
+//	g, _ := idgen.NewFromConfig(cfg)
+//	r, _ := analysis.Analyze(g, ids, analysis.Options{})
+//	r.WriteText(os.Stdout)
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+// DefaultProcesses are the process counts used for collision estimates when
+// Options.Processes is empty.
+var DefaultProcesses = []int{2, 4, 16, 64}
+
+// maxPairSamples bounds the O(n^2) order statistics; larger samples use
+// their first maxPairSamples IDs.
+const maxPairSamples = 4000
+
+// Options tunes Analyze.
+type Options struct {
+	// Processes lists how many unsynchronized processes sharing the
+	// configuration to estimate collisions for. Defaults to DefaultProcesses.
+	Processes []int
+	// Now is the reference time for the remaining horizon. Defaults to time.Now.
+	Now time.Time
+}
+
+// Collision estimates, for n processes issuing independently with the same
+// configuration, the probability that a given ID is also issued by another
+// process.
+type Collision struct {
+	Processes int `json:"processes"`
+	// PerID assumes every process issues at the rate observed in the sample.
+	PerID float64 `json:"per_id"`
+	// PerIDAtMaxRate assumes every process issues one ID per pace.
+	PerIDAtMaxRate float64 `json:"per_id_at_max_rate"`
+}
+
+// Report is the result of Analyze.
+type Report struct {
+	Fingerprint string `json:"fingerprint"`
+	Width       int    `json:"width"`
+	Bits        uint   `json:"bits"`
+	RandomBits  uint   `json:"random_bits"`
+	Pace        string `json:"pace"`
+
+	Samples int `json:"samples"` // IDs that parsed to time-based values
+	Invalid int `json:"invalid"` // IDs that failed to parse
+	Derived int `json:"derived"` // key-derived IDs, excluded from timing
+
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+	IssueRate float64   `json:"issue_rate_per_second"` // estimated from the sample span
+
+	// OrderCorrelation is Kendall's tau between the order of the codes and
+	// the order of issue: 0 means unrelated, ±1 fully ordered.
+	OrderCorrelation float64 `json:"order_correlation"`
+	OrderZ           float64 `json:"order_z"`
+	OrderRevealing   bool    `json:"order_revealing"` // |OrderZ| > 3
+	// AdjacentPrefixShare is how often IDs issued consecutively share their
+	// first character, against 1/36 for unrelated codes.
+	AdjacentPrefixShare float64 `json:"adjacent_prefix_share"`
+
+	CodeSpace       float64 `json:"code_space"`       // 36^width
+	DomainSize      float64 `json:"domain_size"`      // 2^bits
+	CodeUtilization float64 `json:"code_utilization"` // DomainSize / CodeSpace
+	TickUtilization float64 `json:"tick_utilization"` // distinct ticks / ticks spanned by the sample
+
+	Collisions []Collision `json:"collisions"`
+
+	Horizon          time.Time     `json:"horizon"`
+	HorizonRemaining time.Duration `json:"horizon_remaining_ns"`
+
+	Notes []string `json:"notes"`
+}
+
+type sample struct {
+	code string
+	raw  int64
+	tick int64
+}
+
+// Analyze inspects ids (formatted by g or a generator with the same
+// fingerprint). Unparseable IDs are counted as invalid rather than failing the
+// analysis.
+func Analyze(g *idgen.Generator, ids []string, opts Options) (*Report, error) {
+	if g == nil {
+		return nil, errors.New("analysis: generator cannot be nil")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if len(opts.Processes) == 0 {
+		opts.Processes = DefaultProcesses
+	}
+	r := &Report{
+		Fingerprint: g.Fingerprint(),
+		Width:       g.Width(),
+		Bits:        g.Bits(),
+		RandomBits:  g.RandomBits(),
+		Pace:        g.Pace().String(),
+		CodeSpace:   math.Pow(36, float64(g.Width())),
+		DomainSize:  math.Ldexp(1, int(g.Bits())),
+		Horizon:     g.Horizon(),
+	}
+	r.CodeUtilization = math.Min(r.DomainSize/r.CodeSpace, 1)
+	r.HorizonRemaining = r.Horizon.Sub(opts.Now)
+
+	var samples []sample
+	for _, id := range ids {
+		raw, err := g.Parse(id)
+		if err != nil {
+			r.Invalid++
+			continue
+		}
+		if g.IsDerived(raw) {
+			r.Derived++
+			continue
+		}
+		tick, _ := g.Split(raw)
+		code := strings.ReplaceAll(g.Format(raw), "-", "")
+		samples = append(samples, sample{code: code, raw: raw, tick: tick})
+	}
+	r.Samples = len(samples)
+	sort.Slice(samples, func(i, j int) bool { return samples[i].raw < samples[j].raw })
+
+	if len(samples) > 0 {
+		r.First = g.TimestampFromRaw(samples[0].raw)
+		r.Last = g.TimestampFromRaw(samples[len(samples)-1].raw)
+	}
+	if span := r.Last.Sub(r.First); len(samples) > 1 && span > 0 {
+		r.IssueRate = float64(len(samples)-1) / span.Seconds()
+	}
+	if len(samples) > 1 {
+		ticks := map[int64]bool{}
+		shared := 0
+		for i, s := range samples {
+			ticks[s.tick] = true
+			if i > 0 && s.code[0] == samples[i-1].code[0] {
+				shared++
+			}
+		}
+		r.TickUtilization = float64(len(ticks)) / float64(samples[len(samples)-1].tick-samples[0].tick+1)
+		r.AdjacentPrefixShare = float64(shared) / float64(len(samples)-1)
+		r.OrderCorrelation, r.OrderZ = kendall(samples)
+		r.OrderRevealing = math.Abs(r.OrderZ) > 3
+	}
+
+	perTick := math.Min(r.IssueRate*g.Pace().Seconds(), 1)
+	for _, n := range opts.Processes {
+		if n < 2 {
+			continue
+		}
+		r.Collisions = append(r.Collisions, Collision{
+			Processes:      n,
+			PerID:          collision(perTick, g.RandomBits(), n),
+			PerIDAtMaxRate: collision(1, g.RandomBits(), n),
+		})
+	}
+	r.Notes = notes(g, r)
+	return r, nil
+}
+
+// kendall returns Kendall's tau between issue order (samples are sorted by
+// raw value) and code order, with its z-score under independence.
+func kendall(samples []sample) (tau, z float64) {
+	if len(samples) > maxPairSamples {
+		samples = samples[:maxPairSamples]
+	}
+	n := len(samples)
+	var concordant, discordant float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			switch c := strings.Compare(samples[i].code, samples[j].code); {
+			case c < 0:
+				concordant++
+			case c > 0:
+				discordant++
+			}
+		}
+	}
+	pairs := float64(n) * float64(n-1) / 2
+	tau = (concordant - discordant) / pairs
+	variance := 2 * float64(2*n+5) / (9 * float64(n) * float64(n-1))
+	return tau, tau / math.Sqrt(variance)
+}
+
+// collision is the probability that one process's ID is also issued by one of
+// the other n-1 processes, each issuing in a given tick with probability
+// perTick and drawing randBits random bits.
+func collision(perTick float64, randBits uint, n int) float64 {
+	p := perTick * math.Ldexp(1, -int(randBits))
+	return 1 - math.Pow(1-p, float64(n-1))
+}
+
+func notes(g *idgen.Generator, r *Report) []string {
+	var out []string
+	if _, err := g.Config(); err == nil {
+		out = append(out, "The obfuscator is fully determined by the configuration: anyone who knows it can decode exact timestamps, and so the elapsed time and issue rate between any two IDs.")
+	}
+	if r.OrderRevealing {
+		out = append(out, fmt.Sprintf("Code order follows issue order (tau=%.2f, z=%.1f): sorting IDs reveals which came first.", r.OrderCorrelation, r.OrderZ))
+	}
+	if r.Samples > 1 && r.AdjacentPrefixShare > 3.0/36 {
+		out = append(out, fmt.Sprintf("Consecutive IDs share their first character %.0f%% of the time (unrelated codes: 3%%).", 100*r.AdjacentPrefixShare))
+	}
+	if g.RandomBits() == 0 {
+		out = append(out, "Without random bits, IDs are enumerable from a time range, and processes sharing this configuration collide whenever they issue in the same tick; use WithRandomBits or a node field in a Layout.")
+	}
+	if r.HorizonRemaining < 365*24*time.Hour {
+		out = append(out, fmt.Sprintf("The horizon (%s) is less than a year away.", r.Horizon.Format(time.RFC3339)))
+	}
+	return out
+}
+
+// WriteJSON writes r as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes r in a human-readable form.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "configuration   %s (width %d, %d bits, %d random, pace %s)\n", r.Fingerprint, r.Width, r.Bits, r.RandomBits, r.Pace)
+	fmt.Fprintf(&b, "samples         %d valid, %d invalid, %d derived\n", r.Samples, r.Invalid, r.Derived)
+	if r.Samples > 0 {
+		fmt.Fprintf(&b, "time range      %s .. %s\n", r.First.Format(time.RFC3339), r.Last.Format(time.RFC3339))
+		fmt.Fprintf(&b, "issue rate      %.3g/s\n", r.IssueRate)
+	}
+	fmt.Fprintf(&b, "order revealing %t (tau %.3f, z %.1f, adjacent prefix share %.3f)\n", r.OrderRevealing, r.OrderCorrelation, r.OrderZ, r.AdjacentPrefixShare)
+	fmt.Fprintf(&b, "code space      %.3g codes, %.3g used by the domain (%.1f%%), tick utilization %.3g\n", r.CodeSpace, r.DomainSize, 100*r.CodeUtilization, r.TickUtilization)
+	for _, c := range r.Collisions {
+		fmt.Fprintf(&b, "collisions      %d processes: %.3g per ID at observed rate, %.3g at max rate\n", c.Processes, c.PerID, c.PerIDAtMaxRate)
+	}
+	fmt.Fprintf(&b, "horizon         %s (%s remaining)\n", r.Horizon.Format(time.RFC3339), r.HorizonRemaining.Round(time.Hour))
+	for _, n := range r.Notes {
+		fmt.Fprintf(&b, "note: %s\n", n)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
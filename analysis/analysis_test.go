@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+func sampleIDs(t *testing.T, g *idgen.Generator, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = g.Format(g.Generate())
+	}
+	return ids
+}
+
+func TestAnalyzeDefault(t *testing.T) {
+	g, err := idgen.New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ids := append(sampleIDs(t, g, 200), "not-an-id!")
+	now := time.Now()
+	r, err := Analyze(g, ids, Options{Now: now})
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	if r.Samples != 200 || r.Invalid != 1 {
+		t.Fatalf("samples=%d invalid=%d, want 200 and 1", r.Samples, r.Invalid)
+	}
+	if r.OrderRevealing {
+		t.Fatalf("default Feistel reported order revealing (tau %.3f, z %.1f)", r.OrderCorrelation, r.OrderZ)
+	}
+	if r.IssueRate <= 0 || r.IssueRate > 1001 {
+		t.Fatalf("IssueRate = %v, want within (0, 1000]", r.IssueRate)
+	}
+	if want := math.Ldexp(1, 41) / math.Pow(36, 8); math.Abs(r.CodeUtilization-want) > 1e-9 {
+		t.Fatalf("CodeUtilization = %v, want %v", r.CodeUtilization, want)
+	}
+	if len(r.Collisions) != len(DefaultProcesses) || r.Collisions[0].PerIDAtMaxRate != 1 {
+		t.Fatalf("Collisions = %+v, want certain collisions at max rate without random bits", r.Collisions)
+	}
+	if r.HorizonRemaining != g.Horizon().Sub(now) {
+		t.Fatalf("HorizonRemaining = %v", r.HorizonRemaining)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON error: %v", err)
+	}
+	var back Report
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil || back.Samples != 200 || back.Fingerprint != g.Fingerprint() {
+		t.Fatalf("JSON round-trip = %+v, %v", back, err)
+	}
+	buf.Reset()
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	for _, want := range []string{"order revealing false", "collisions      2 processes", "horizon", "note:"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("text report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestAnalyzeDetectsOrderAndRandomness(t *testing.T) {
+	identity, _ := idgen.NewXorMask(41, 0)
+	g, err := idgen.New(idgen.WithObfuscation(identity))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	r, err := Analyze(g, sampleIDs(t, g, 50), Options{})
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	if !r.OrderRevealing || r.OrderCorrelation != 1 {
+		t.Fatalf("identity obfuscator: revealing=%t tau=%v, want true and 1", r.OrderRevealing, r.OrderCorrelation)
+	}
+
+	rg, err := idgen.New(idgen.WithWidth(10), idgen.WithRandomBits(12))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	r, err = Analyze(rg, sampleIDs(t, rg, 20), Options{Processes: []int{2}})
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	if c := r.Collisions[0].PerIDAtMaxRate; math.Abs(c-math.Ldexp(1, -12)) > 1e-12 {
+		t.Fatalf("PerIDAtMaxRate = %v, want 2^-12", c)
+	}
+	if _, err := Analyze(nil, nil, Options{}); err == nil {
+		t.Fatal("expected error for nil generator")
+	}
+}
+
+func TestAnalyzeNormalizesInput(t *testing.T) {
+	// Without obfuscation every current ID starts with "0", which the
+	// unpadded inputs drop.
+	identity, _ := idgen.NewXorMask(41, 0)
+	g, err := idgen.New(idgen.WithObfuscation(identity))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ids := sampleIDs(t, g, 50)
+	mangled := make([]string, len(ids))
+	for i, id := range ids {
+		mangled[i] = strings.ToUpper(id)
+		if i%2 == 1 {
+			mangled[i] = " " + strings.TrimLeft(strings.ReplaceAll(mangled[i], "-", ""), "0") + " "
+		}
+	}
+	want, err := Analyze(g, ids, Options{})
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	got, err := Analyze(g, mangled, Options{})
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	if got.Samples != want.Samples || got.AdjacentPrefixShare != want.AdjacentPrefixShare {
+		t.Fatalf("mangled input: samples=%d prefix share=%v, want %d and %v",
+			got.Samples, got.AdjacentPrefixShare, want.Samples, want.AdjacentPrefixShare)
+	}
+}
@@ -0,0 +1,15 @@
+package idgen
+
+// DefaultBlocklist returns a small built-in list of English offensive words for
+// WithBlocklist. It is intentionally conservative; extend it as needed:
+//
+//	idgen.WithBlocklist(append(idgen.DefaultBlocklist(), "acme"))
+func DefaultBlocklist() []string {
+	return []string{
+		"anal", "anus", "arse", "ass", "bitch", "boob", "butt", "cock",
+		"coon", "crap", "cum", "cunt", "damn", "dick", "dildo", "dyke",
+		"fag", "fuck", "gay", "hell", "homo", "jizz", "kike", "nazi",
+		"nig", "penis", "piss", "poop", "porn", "pussy", "rape", "sex",
+		"shit", "slut", "spic", "tit", "twat", "wank", "whore",
+	}
+}
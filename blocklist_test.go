@@ -0,0 +1,50 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlocklistSkipsTicksDeterministically(t *testing.T) {
+	plainGen, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// Pick a word from a known tick's ID so the outcome does not depend on the clock.
+	const tick = int64(123456789)
+	word := strings.ReplaceAll(plainGen.Format(tick), "-", "")[2:5]
+
+	g, err := New(WithBlocklist([]string{strings.ToUpper(word)}))
+	if err != nil {
+		t.Fatalf("New(WithBlocklist) error: %v", err)
+	}
+	if !g.blocked(tick) {
+		t.Fatalf("tick %d (%s) not blocked by %q", tick, g.Format(tick), word)
+	}
+	// Format and Parse are unaffected by the blocklist.
+	id := g.Format(tick)
+	if id != plainGen.Format(tick) {
+		t.Fatalf("Format changed under blocklist: %q vs %q", id, plainGen.Format(tick))
+	}
+	if back, err := g.Parse(id); err != nil || back != tick {
+		t.Fatalf("Parse(%q) = %d, %v; want %d", id, back, err, tick)
+	}
+
+	for i := 0; i < 5; i++ {
+		if got := g.Format(g.Generate()); strings.Contains(strings.ReplaceAll(got, "-", ""), word) {
+			t.Fatalf("Generate emitted blocked ID %q", got)
+		}
+	}
+}
+
+func TestBlocklistValidation(t *testing.T) {
+	if _, err := New(WithBlocklist(DefaultBlocklist())); err != nil {
+		t.Fatalf("DefaultBlocklist rejected: %v", err)
+	}
+	if _, err := New(WithBlocklist([]string{"a"})); err == nil {
+		t.Fatal("expected error for single-character word")
+	}
+	if _, err := New(WithBlocklist([]string{"no way"})); err == nil {
+		t.Fatal("expected error for non-base36 word")
+	}
+}
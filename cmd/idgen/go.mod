@@ -0,0 +1,8 @@
+module github.com/dan-sherwin/idgen/cmd/idgen
+
+go 1.24
+
+require (
+	github.com/dan-sherwin/idgen v0.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,261 @@
+// Command idgen exposes idgen generators from the command line.
+//
+// Usage:
+//
+//	idgen serve [-addr :8080] [-config settings.yaml] [-epoch ...] [-pace 1ms] [-width 8] [-bits 0] [-rounds 4]
+//	idgen config [-config settings.yaml] [flags]
+//	idgen analyze [-json] [-processes 2,4,16,64] [flags] [file ...]
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+	"github.com/dan-sherwin/idgen/analysis"
+	"github.com/dan-sherwin/idgen/idgenhttp"
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `usage: idgen <command> [flags]
+
+commands:
+  serve   run an HTTP server exposing generate/decode endpoints
+  config  print the effective generator settings as JSON
+  analyze report what a sample of IDs (one per line, from files or stdin)
+          reveals under the settings: ordering, code space use, collision
+          odds across processes and horizon
+
+Generator settings are read from -config FILE (.json/.yaml), or else from
+IDGEN_EPOCH, IDGEN_PACE, IDGEN_WIDTH, IDGEN_BITS, IDGEN_ROUNDS, IDGEN_FULL_WIDTH,
+IDGEN_BLOCKLIST, IDGEN_LAYOUT, IDGEN_RANDOM_BITS and IDGEN_DERIVED_IDS. Flags
+given explicitly override both.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "serve":
+		err = serve(os.Args[2:])
+	case "config":
+		err = printConfig(os.Args[2:])
+	case "analyze":
+		err = analyze(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "idgen: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "idgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generatorFlags registers the flags shared by commands that build a Generator.
+// Settings come from -config (JSON or YAML) or, without it, from IDGEN_*
+// environment variables; flags given explicitly override either source.
+type generatorFlags struct {
+	fs     *flag.FlagSet
+	config string
+	epoch  string
+	pace   time.Duration
+	width  int
+	bits   uint
+	rounds int
+}
+
+func (f *generatorFlags) register(fs *flag.FlagSet) {
+	def := idgen.DefaultConfig()
+	f.fs = fs
+	fs.StringVar(&f.config, "config", "", "settings file (.json, .yaml or .yml)")
+	fs.StringVar(&f.epoch, "epoch", def.Epoch.Format(time.RFC3339), "epoch in RFC3339")
+	fs.DurationVar(&f.pace, "pace", time.Duration(def.Pace), "minimum spacing between IDs")
+	fs.IntVar(&f.width, "width", def.Width, "fixed base36 width")
+	fs.UintVar(&f.bits, "bits", 0, "domain bits (0 derives from width)")
+	fs.IntVar(&f.rounds, "rounds", def.Rounds, "Feistel rounds")
+}
+
+// resolve builds the effective Config from file or environment plus flags.
+func (f *generatorFlags) resolve() (idgen.Config, error) {
+	var cfg idgen.Config
+	var err error
+	if f.config != "" {
+		cfg, err = loadConfig(f.config)
+	} else {
+		cfg, err = idgen.ConfigFromEnv("IDGEN")
+	}
+	if err != nil {
+		return idgen.Config{}, err
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "epoch":
+			var t time.Time
+			if t, err = time.Parse(time.RFC3339, f.epoch); err != nil {
+				err = fmt.Errorf("invalid -epoch %q: %w", f.epoch, err)
+			}
+			cfg.Epoch = t
+		case "pace":
+			cfg.Pace = idgen.Duration(f.pace)
+		case "width":
+			cfg.Width = f.width
+		case "bits":
+			cfg.Bits = f.bits
+		case "rounds":
+			cfg.Rounds = f.rounds
+		}
+	})
+	if err != nil {
+		return idgen.Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (f *generatorFlags) generator(opts ...idgen.Option) (*idgen.Generator, error) {
+	cfg, err := f.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return idgen.NewFromConfig(cfg, opts...)
+}
+
+// loadConfig reads a Config from a JSON or YAML file, chosen by extension.
+func loadConfig(path string) (idgen.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return idgen.Config{}, err
+	}
+	var cfg idgen.Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		return idgen.Config{}, fmt.Errorf("config %s: unsupported extension (want .json, .yaml or .yml)", path)
+	}
+	if err != nil {
+		return idgen.Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// printConfig writes the effective settings as JSON, suitable for -config.
+func printConfig(args []string) error {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	var gf generatorFlags
+	gf.register(fs)
+	_ = fs.Parse(args)
+	g, err := gf.generator()
+	if err != nil {
+		return err
+	}
+	cfg, err := g.Config()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
+
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "listen address")
+	maxBatch := fs.Int("max-batch", 1000, "maximum IDs per POST /ids request")
+	var gf generatorFlags
+	gf.register(fs)
+	_ = fs.Parse(args)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	g, err := gf.generator(idgen.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	h, err := idgenhttp.New(g, idgenhttp.WithMaxBatch(*maxBatch))
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Addr: *addr, Handler: h, ReadHeaderTimeout: 10 * time.Second}
+	logger.Info("idgen: serving", "addr", *addr, "width", g.Width(), "bits", g.Bits(), "horizon", g.Horizon())
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// analyze reads IDs from the named files, or stdin without any, and prints an
+// analysis.Report as text or JSON.
+func analyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	procs := fs.String("processes", "2,4,16,64", "comma-separated process counts for collision estimates")
+	var gf generatorFlags
+	gf.register(fs)
+	_ = fs.Parse(args)
+
+	var opts analysis.Options
+	for _, p := range strings.Split(*procs, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n < 2 {
+			return fmt.Errorf("invalid -processes entry %q (want integers >= 2)", p)
+		}
+		opts.Processes = append(opts.Processes, n)
+	}
+	g, err := gf.generator()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	readIDs := func(r io.Reader) error {
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			if line := strings.TrimSpace(sc.Text()); line != "" {
+				ids = append(ids, line)
+			}
+		}
+		return sc.Err()
+	}
+	if fs.NArg() == 0 {
+		if err := readIDs(os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = readIDs(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	r, err := analysis.Analyze(g, ids, opts)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return r.WriteJSON(os.Stdout)
+	}
+	return r.WriteText(os.Stdout)
+}
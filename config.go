@@ -0,0 +1,313 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is a serializable description of a Generator. Producers and consumers
+// of the same IDs should load one shared Config so that Parse agrees with Format.
+// Zero-valued fields take the defaults documented on New.
+type Config struct {
+	Epoch      time.Time `json:"epoch" yaml:"epoch"`
+	Pace       Duration  `json:"pace" yaml:"pace"`
+	Width      int       `json:"width" yaml:"width"`
+	Bits       uint      `json:"bits,omitempty" yaml:"bits,omitempty"`               // 0 derives from width
+	Rounds     int       `json:"rounds" yaml:"rounds"`                               // Feistel rounds
+	FullWidth  bool      `json:"full_width,omitempty" yaml:"full_width,omitempty"`   // see WithFullWidth
+	Blocklist  []string  `json:"blocklist,omitempty" yaml:"blocklist,omitempty"`     // see WithBlocklist
+	Layout     string    `json:"layout,omitempty" yaml:"layout,omitempty"`           // ParseLayout syntax; see WithLayout
+	RandomBits uint      `json:"random_bits,omitempty" yaml:"random_bits,omitempty"` // see WithRandomBits
+	DerivedIDs bool      `json:"derived_ids,omitempty" yaml:"derived_ids,omitempty"` // see WithDerivedIDs
+	Alphabet   string    `json:"alphabet,omitempty" yaml:"alphabet,omitempty"`       // "base36" (default) or "vowel-free"
+}
+
+// Duration is a time.Duration that serializes as a string such as "1ms".
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// DefaultConfig returns the configuration New uses without options.
+func DefaultConfig() Config {
+	return Config{
+		Epoch:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Pace:   Duration(time.Millisecond),
+		Width:  8,
+		Rounds: 4,
+	}
+}
+
+// withDefaults fills zero-valued fields from DefaultConfig.
+func (c Config) withDefaults() Config {
+	def := DefaultConfig()
+	if c.Epoch.IsZero() {
+		c.Epoch = def.Epoch
+	}
+	if c.Pace == 0 {
+		c.Pace = def.Pace
+	}
+	if c.Width == 0 {
+		c.Width = def.Width
+	}
+	if c.Rounds == 0 {
+		c.Rounds = def.Rounds
+	}
+	return c
+}
+
+// Validate reports every problem with c, naming the offending field and value.
+func (c Config) Validate() error {
+	c = c.withDefaults()
+	var errs []error
+	if c.Pace < 0 {
+		errs = append(errs, fmt.Errorf("config: pace must be > 0, got %s", time.Duration(c.Pace)))
+	}
+	if c.Width < 1 {
+		errs = append(errs, fmt.Errorf("config: width must be >= 1, got %d", c.Width))
+	}
+	if c.Bits > 63 {
+		errs = append(errs, fmt.Errorf("config: bits must be in [1,63], got %d", c.Bits))
+	}
+	if c.Rounds < 2 {
+		errs = append(errs, fmt.Errorf("config: rounds must be >= 2, got %d", c.Rounds))
+	}
+	if c.Layout != "" {
+		l, err := ParseLayout(c.Layout)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("config: %w", err))
+		case c.FullWidth:
+			errs = append(errs, fmt.Errorf("config: layout cannot be set with full_width"))
+		case c.Bits != 0 && c.Bits != l.Bits():
+			errs = append(errs, fmt.Errorf("config: layout %q has %d bits but bits is %d", c.Layout, l.Bits(), c.Bits))
+		default:
+			c.Bits = l.Bits()
+		}
+	}
+	if c.RandomBits != 0 {
+		switch {
+		case c.RandomBits > 62:
+			errs = append(errs, fmt.Errorf("config: random_bits must be in [1,62], got %d", c.RandomBits))
+		case c.Layout != "" || c.FullWidth:
+			errs = append(errs, fmt.Errorf("config: random_bits cannot be set with layout or full_width"))
+		case c.Bits != 0 && c.RandomBits >= c.Bits:
+			errs = append(errs, fmt.Errorf("config: random_bits %d leaves no room for the tick in %d bits", c.RandomBits, c.Bits))
+		}
+	}
+	if c.DerivedIDs && c.FullWidth {
+		errs = append(errs, fmt.Errorf("config: derived_ids cannot be set with full_width"))
+	}
+	alpha, ok := alphabetByName(c.Alphabet)
+	if !ok {
+		errs = append(errs, fmt.Errorf("config: alphabet must be %q or %q, got %q", alphabet36.name, alphabetVowelFree.name, c.Alphabet))
+		alpha = alphabet36
+	}
+	if c.Width >= 1 {
+		maxBits := alpha.maxBits(c.Width)
+		switch {
+		case c.FullWidth && c.Bits != 0:
+			errs = append(errs, fmt.Errorf("config: bits cannot be set with full_width (derived from width)"))
+		case c.FullWidth && c.Width > 12:
+			errs = append(errs, fmt.Errorf("config: full_width requires width <= 12, got %d", c.Width))
+		case !c.FullWidth && c.Bits == 0 && maxBits > 63:
+			errs = append(errs, fmt.Errorf("config: width %d derives %d bits (max 63); set bits explicitly", c.Width, maxBits))
+		case !c.FullWidth && c.Bits > maxBits && c.Bits <= 63:
+			errs = append(errs, fmt.Errorf("config: width %d too small for %d bits (max %d)", c.Width, c.Bits, maxBits))
+		}
+	}
+	for _, w := range c.Blocklist {
+		if err := checkBlockWord(strings.ToLower(w)); err != nil {
+			errs = append(errs, fmt.Errorf("config: %w", err))
+		}
+	}
+	if len(errs) == 0 {
+		// Checks that depend on the current time, such as the horizon.
+		if _, err := New(c.Options()...); err != nil {
+			errs = append(errs, fmt.Errorf("config: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Options converts c into the equivalent New options.
+func (c Config) Options() []Option {
+	c = c.withDefaults()
+	opts := []Option{WithEpoch(c.Epoch), WithPace(time.Duration(c.Pace)), WithWidth(c.Width)}
+	if c.Bits != 0 {
+		opts = append(opts, WithBits(c.Bits))
+	}
+	opts = append(opts, withRounds(c.Rounds))
+	if c.FullWidth {
+		opts = append(opts, WithFullWidth())
+	}
+	if len(c.Blocklist) > 0 {
+		opts = append(opts, WithBlocklist(c.Blocklist))
+	}
+	if c.Layout != "" {
+		opts = append(opts, withLayoutSpec(c.Layout))
+	}
+	if c.RandomBits != 0 {
+		opts = append(opts, WithRandomBits(c.RandomBits))
+	}
+	if c.DerivedIDs {
+		opts = append(opts, WithDerivedIDs())
+	}
+	if c.Alphabet == alphabetVowelFree.name {
+		opts = append(opts, WithVowelFreeAlphabet())
+	}
+	return opts
+}
+
+// withLayoutSpec is WithLayout for a ParseLayout spec.
+func withLayoutSpec(spec string) Option {
+	return func(g *Generator) error {
+		l, err := ParseLayout(spec)
+		if err != nil {
+			return err
+		}
+		g.layout = l
+		return nil
+	}
+}
+
+// withRounds selects the number of rounds for the default Feistel obfuscator.
+func withRounds(rounds int) Option {
+	return func(g *Generator) error {
+		if rounds < 2 {
+			return errors.New("rounds must be >= 2")
+		}
+		g.rounds = rounds
+		return nil
+	}
+}
+
+// NewFromConfig validates c and constructs a Generator from it. Additional
+// options (observers, loggers, ...) are applied after the config.
+func NewFromConfig(c Config, opts ...Option) (*Generator, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return New(append(c.Options(), opts...)...)
+}
+
+// Config exports the generator's settings. It fails if the generator uses a
+// custom or keyed obfuscator or a custom range permutation, which Config
+// cannot describe.
+func (g *Generator) Config() (Config, error) {
+	c := Config{
+		Epoch:     g.Epoch(),
+		Pace:      Duration(g.pace),
+		Width:     g.width,
+		Bits:      g.bits,
+		Blocklist: append([]string(nil), g.blocklist...),
+	}
+	if g.layout != nil {
+		c.Layout = g.layout.String()
+	}
+	c.RandomBits = g.randBits
+	c.DerivedIDs = g.derived
+	if g.alpha != alphabet36 {
+		c.Alphabet = g.alpha.name
+	}
+	if g.rp != nil {
+		n, ok := g.alpha.pow(g.width)
+		if !ok || g.rp.n != n {
+			return Config{}, errors.New("config: custom range permutation cannot be exported")
+		}
+		c.FullWidth = true
+		c.Bits = 0
+	}
+	f, ok := g.ob.(*feistel)
+	if !ok || f.keyed {
+		return Config{}, errors.New("config: custom obfuscator cannot be exported")
+	}
+	c.Rounds = f.rounds
+	return c, nil
+}
+
+// ConfigFromEnv starts from DefaultConfig and overrides fields from
+// environment variables named prefix + "_" + field: EPOCH (RFC3339), PACE
+// (duration), WIDTH, BITS, ROUNDS, FULL_WIDTH (bool), BLOCKLIST
+// (comma-separated), LAYOUT, RANDOM_BITS, DERIVED_IDS (bool) and ALPHABET.
+// Unset variables keep their defaults.
+func ConfigFromEnv(prefix string) (Config, error) {
+	c := DefaultConfig()
+	var errs []error
+	lookup := func(name string, parse func(string) error) {
+		key := prefix + "_" + name
+		if v, ok := os.LookupEnv(key); ok {
+			if err := parse(strings.TrimSpace(v)); err != nil {
+				errs = append(errs, fmt.Errorf("config: env %s=%q: %w", key, v, err))
+			}
+		}
+	}
+	lookup("EPOCH", func(v string) (err error) {
+		c.Epoch, err = time.Parse(time.RFC3339, v)
+		return err
+	})
+	lookup("PACE", func(v string) error { return c.Pace.UnmarshalText([]byte(v)) })
+	lookup("WIDTH", func(v string) (err error) {
+		c.Width, err = strconv.Atoi(v)
+		return err
+	})
+	lookup("BITS", func(v string) error {
+		n, err := strconv.ParseUint(v, 10, 8)
+		c.Bits = uint(n)
+		return err
+	})
+	lookup("ROUNDS", func(v string) (err error) {
+		c.Rounds, err = strconv.Atoi(v)
+		return err
+	})
+	lookup("FULL_WIDTH", func(v string) (err error) {
+		c.FullWidth, err = strconv.ParseBool(v)
+		return err
+	})
+	lookup("BLOCKLIST", func(v string) error {
+		c.Blocklist = nil
+		for _, w := range strings.Split(v, ",") {
+			if w = strings.TrimSpace(w); w != "" {
+				c.Blocklist = append(c.Blocklist, w)
+			}
+		}
+		return nil
+	})
+	lookup("LAYOUT", func(v string) error {
+		c.Layout = v
+		return nil
+	})
+	lookup("RANDOM_BITS", func(v string) error {
+		n, err := strconv.ParseUint(v, 10, 8)
+		c.RandomBits = uint(n)
+		return err
+	})
+	lookup("DERIVED_IDS", func(v string) (err error) {
+		c.DerivedIDs, err = strconv.ParseBool(v)
+		return err
+	})
+	lookup("ALPHABET", func(v string) error {
+		c.Alphabet = v
+		return nil
+	})
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,99 @@
+package idgen
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigJSONRoundTrip(t *testing.T) {
+	g, err := NewFromConfig(Config{
+		Epoch:     time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Pace:      Duration(2 * time.Millisecond),
+		Width:     9,
+		Rounds:    6,
+		Blocklist: []string{"abc"},
+	})
+	if err != nil {
+		t.Fatalf("NewFromConfig error: %v", err)
+	}
+	cfg, err := g.Config()
+	if err != nil {
+		t.Fatalf("Config error: %v", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"pace":"2ms"`) {
+		t.Fatalf("pace not serialized as duration string: %s", data)
+	}
+	var back Config
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	g2, err := NewFromConfig(back)
+	if err != nil {
+		t.Fatalf("NewFromConfig(round-trip) error: %v", err)
+	}
+	for _, raw := range []int64{0, 1, 123456789} {
+		if a, b := g.Format(raw), g2.Format(raw); a != b {
+			t.Fatalf("Format(%d) differs after round-trip: %q vs %q", raw, a, b)
+		}
+	}
+	if g2.Pace() != 2*time.Millisecond || g2.Width() != 9 || !g2.Epoch().Equal(g.Epoch()) {
+		t.Fatalf("round-trip config mismatch: %+v", back)
+	}
+
+	full, _ := New(WithWidth(4), WithFullWidth(), WithEpoch(time.Now()))
+	if c, err := full.Config(); err != nil || !c.FullWidth || c.Bits != 0 {
+		t.Fatalf("full-width Config() = %+v, %v", c, err)
+	}
+	ob, _ := NewXorMask(41, 1)
+	custom, _ := New(WithObfuscation(ob))
+	if _, err := custom.Config(); err == nil {
+		t.Fatal("expected error exporting a custom obfuscator")
+	}
+}
+
+func TestConfigValidateMessages(t *testing.T) {
+	err := Config{Pace: Duration(-time.Millisecond), Width: 2, Bits: 40, Rounds: 1, Blocklist: []string{"x"}}.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"pace must be > 0, got -1ms", "rounds must be >= 2, got 1", "width 2 too small for 40 bits (max 10)", `blocklist word "x" too short`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Validate() = %q, missing %q", err, want)
+		}
+	}
+	if err := (Config{Width: 13}).Validate(); err == nil || !strings.Contains(err.Error(), "set bits explicitly") {
+		t.Fatalf("Validate(width 13) = %v", err)
+	}
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("DefaultConfig invalid: %v", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("IDGEN_EPOCH", "2024-01-01T00:00:00Z")
+	t.Setenv("IDGEN_PACE", "5ms")
+	t.Setenv("IDGEN_WIDTH", "10")
+	t.Setenv("IDGEN_BITS", "45")
+	t.Setenv("IDGEN_BLOCKLIST", "foo, bar")
+	c, err := ConfigFromEnv("IDGEN")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv error: %v", err)
+	}
+	if !c.Epoch.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || time.Duration(c.Pace) != 5*time.Millisecond ||
+		c.Width != 10 || c.Bits != 45 || c.Rounds != 4 || len(c.Blocklist) != 2 || c.Blocklist[1] != "bar" {
+		t.Fatalf("ConfigFromEnv = %+v", c)
+	}
+
+	t.Setenv("IDGEN_PACE", "soon")
+	t.Setenv("IDGEN_WIDTH", "wide")
+	_, err = ConfigFromEnv("IDGEN")
+	if err == nil || !strings.Contains(err.Error(), `IDGEN_PACE="soon"`) || !strings.Contains(err.Error(), `IDGEN_WIDTH="wide"`) {
+		t.Fatalf("ConfigFromEnv(invalid) error = %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package idgen
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFromKey(t *testing.T) {
+	g, err := New(WithDerivedIDs())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	fromKey := func(namespace, key string) string {
+		t.Helper()
+		id, err := g.FromKey([]byte(namespace), []byte(key))
+		if err != nil {
+			t.Fatalf("FromKey(%q, %q) error: %v", namespace, key, err)
+		}
+		return id.String()
+	}
+	a := fromKey("partner-a", "order-1001")
+	if b := fromKey("partner-a", "order-1001"); a != b {
+		t.Fatalf("FromKey not deterministic: %q vs %q", a, b)
+	}
+	seen := map[string]bool{a: true}
+	for _, k := range [][2]string{{"partner-b", "order-1001"}, {"partner-a", "order-1002"}, {"partner-ao", "rder-1001"}} {
+		id := fromKey(k[0], k[1])
+		if seen[id] {
+			t.Fatalf("FromKey(%q, %q) collided: %q", k[0], k[1], id)
+		}
+		seen[id] = true
+	}
+	if len(a) != len(g.Format(g.Generate())) {
+		t.Fatalf("FromKey = %q, want the Format width", a)
+	}
+	raw, err := g.Parse(a)
+	if err != nil || !g.IsDerived(raw) {
+		t.Fatalf("Parse(%q) = %d, %v; want a derived raw value", a, raw, err)
+	}
+	if _, err := g.TimestampFromID(a); !errors.Is(err, ErrDerivedID) {
+		t.Fatalf("TimestampFromID(%q) error = %v, want ErrDerivedID", a, err)
+	}
+	timed := g.Format(g.Generate())
+	if _, err := g.TimestampFromID(timed); err != nil {
+		t.Fatalf("TimestampFromID(%q) error: %v", timed, err)
+	}
+
+	plain, _ := New()
+	if want := plain.TimestampFromRaw(plain.horizonTick >> 1); !g.Horizon().Equal(want) {
+		t.Fatalf("Horizon() = %v, want %v", g.Horizon(), want)
+	}
+	if id, err := plain.FromKey(nil, []byte("x")); err == nil {
+		t.Fatalf("FromKey without WithDerivedIDs = %q, want error", id)
+	}
+}
+
+func TestDerivedIDHasNoTimestamp(t *testing.T) {
+	g, err := New(WithDerivedIDs())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	id, err := g.FromKey([]byte("partner-a"), []byte("order-1001"))
+	if err != nil {
+		t.Fatalf("FromKey error: %v", err)
+	}
+	if !id.IsDerived() || !id.Time().IsZero() {
+		t.Fatalf("FromKey ID: IsDerived = %v, Time = %v; want true and zero", id.IsDerived(), id.Time())
+	}
+	if timed := g.GenerateID(); timed.IsDerived() || timed.Time().IsZero() {
+		t.Fatalf("GenerateID: IsDerived = %v, Time = %v; want false and a timestamp", timed.IsDerived(), timed.Time())
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("imported", "order", id)
+	if log := buf.String(); !strings.Contains(log, `"derived":true`) || strings.Contains(log, "created_at") {
+		t.Fatalf("logged %s, want derived=true and no created_at", log)
+	}
+
+	if f, err := g.Decode(id.String()); !errors.Is(err, ErrDerivedID) {
+		t.Fatalf("Decode(%q) = %v, %v; want ErrDerivedID", id, f, err)
+	}
+
+	matches := g.FindAll("imported " + id.String() + " from partner-a")
+	if len(matches) != 1 || !matches[0].Derived || !matches[0].Time.IsZero() || matches[0].Raw != id.Raw() {
+		t.Fatalf("FindAll = %+v, want one derived match with no time", matches)
+	}
+}
@@ -1,6 +1,10 @@
 package idgen
 
-import "fmt"
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
 
 // feistel implements a k-bit Feistel network as an Obfuscator.
 // It uses simple ARX-style round functions with fixed, non-secret constants.
@@ -9,9 +13,25 @@ type feistel struct {
 	rounds int
 	lBits  uint // left half bits (initial)
 	rBits  uint // right half bits (initial)
+	lMask  uint64
+	rMask  uint64
 	mask   uint64
+	rk     []roundKey // per-round constants, precomputed in NewFeistel
+	keyed  bool       // round constants derived from a key; see NewKeyedFeistel
 }
 
+// roundKey holds the constants simpleFbits needs for one round.
+type roundKey struct {
+	c       uint64 // round constant, pre-masked to the input width
+	inMask  uint64
+	outMask uint64
+	outBits uint
+	rot     uint
+}
+
+// roundConstants are fixed, non-secret ARX constants cycled across rounds.
+var roundConstants = [...]uint64{0x9E3779B97F4A7C15, 0xBF58476D1CE4E5B9, 0x94D049BB133111EB, 0xD6E8FEB86659FD93, 0xA24BAED4963EE407, 0x9FB21C651E98DF25}
+
 // NewFeistel creates a k-bit Feistel obfuscator with the given number of rounds.
 // k must be in [1, 63], rounds >= 2.
 func NewFeistel(k uint, rounds int) (Obfuscator, error) {
@@ -23,48 +43,84 @@ func NewFeistel(k uint, rounds int) (Obfuscator, error) {
 	}
 	l := (k + 1) / 2 // favor left = ceil(k/2)
 	r := k - l
-	return &feistel{
+	f := &feistel{
 		k:      k,
 		rounds: rounds,
 		lBits:  l,
 		rBits:  r,
+		lMask:  (uint64(1) << l) - 1,
+		rMask:  (uint64(1) << r) - 1,
 		mask:   (uint64(1) << k) - 1,
-	}, nil
+		rk:     make([]roundKey, rounds),
+	}
+	for i := range f.rk {
+		// Even rounds map R (rBits) -> lBits; odd rounds map lBits -> rBits.
+		inBits, outBits := r, l
+		if i%2 == 1 {
+			inBits, outBits = l, r
+		}
+		inMask := (uint64(1) << inBits) - 1
+		rk := roundKey{
+			c:       roundConstants[i%len(roundConstants)] & inMask,
+			inMask:  inMask,
+			outMask: (uint64(1) << outBits) - 1,
+			outBits: outBits,
+		}
+		if outBits > 1 {
+			rk.rot = (5 + uint(i)) % outBits
+		}
+		f.rk[i] = rk
+	}
+	return f, nil
+}
+
+// NewKeyedFeistel is like NewFeistel but derives the round constants from key,
+// so generators with different keys permute the same ticks differently. It is
+// still obfuscation rather than encryption.
+func NewKeyedFeistel(k uint, rounds int, key []byte) (Obfuscator, error) {
+	ob, err := NewFeistel(k, rounds)
+	if err != nil {
+		return nil, err
+	}
+	f := ob.(*feistel)
+	f.keyed = true
+	for i := range f.rk {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(i))
+		sum := sha256.Sum256(append(append([]byte(nil), key...), n[:]...))
+		f.rk[i].c = binary.BigEndian.Uint64(sum[:8]) & f.rk[i].inMask
+	}
+	return f, nil
 }
 
 func (f *feistel) DomainBits() uint { return f.k }
 
-// simpleFbits maps an input of inBits to an output of outBits using ARX ops.
-func (f *feistel) simpleFbits(input uint64, inBits, outBits uint, round int) uint64 {
-	constC := [...]uint64{0x9E3779B97F4A7C15, 0xBF58476D1CE4E5B9, 0x94D049BB133111EB, 0xD6E8FEB86659FD93, 0xA24BAED4963EE407, 0x9FB21C651E98DF25}
-	c := constC[round%len(constC)]
-	inMask := (uint64(1) << inBits) - 1
-	outMask := (uint64(1) << outBits) - 1
-	x := (input & inMask) ^ (c & inMask)
-	x = (x*0x5bd1e995 + 0x27d4eb2d) & outMask
-	if outBits > 1 {
-		rot := (5 + uint(round)) % outBits
-		x = ((x << rot) | (x >> (outBits - rot))) & outMask
+// simpleFbits maps an input to round i's output width using ARX ops.
+func (f *feistel) simpleFbits(input uint64, round int) uint64 {
+	rk := &f.rk[round]
+	x := (input & rk.inMask) ^ rk.c
+	x = (x*0x5bd1e995 + 0x27d4eb2d) & rk.outMask
+	if rk.outBits > 1 {
+		x = ((x << rk.rot) | (x >> (rk.outBits - rk.rot))) & rk.outMask
 	}
-	x ^= (x >> 3) & outMask
-	return x & outMask
+	x ^= (x >> 3) & rk.outMask
+	return x & rk.outMask
 }
 
 func (f *feistel) Obfuscate(x uint64) uint64 {
 	x &= f.mask
-	lMask := (uint64(1) << f.lBits) - 1
-	rMask := (uint64(1) << f.rBits) - 1
+	lMask, rMask := f.lMask, f.rMask
 	// Initial halves: L has lBits (low), R has rBits (high)
 	L := x & lMask
 	R := (x >> f.lBits) & rMask
 	for i := 0; i < f.rounds; i++ {
 		if i%2 == 0 {
 			// Map R (rBits) -> lBits
-			fn := f.simpleFbits(R, f.rBits, f.lBits, i)
+			fn := f.simpleFbits(R, i)
 			L, R = R, (L^fn)&lMask // new L has rBits, new R has lBits
 		} else {
 			// Map R (lBits) -> rBits (sizes swapped after previous round)
-			fn := f.simpleFbits(R, f.lBits, f.rBits, i)
+			fn := f.simpleFbits(R, i)
 			L, R = R, (L^fn)&rMask // new L has lBits, new R has rBits
 		}
 	}
@@ -79,8 +135,7 @@ func (f *feistel) Obfuscate(x uint64) uint64 {
 
 func (f *feistel) Deobfuscate(y uint64) uint64 {
 	y &= f.mask
-	lMask := (uint64(1) << f.lBits) - 1
-	rMask := (uint64(1) << f.rBits) - 1
+	lMask, rMask := f.lMask, f.rMask
 	// Unpack current halves depending on parity of rounds
 	var L, R uint64
 	if f.rounds%2 == 0 {
@@ -96,13 +151,13 @@ func (f *feistel) Deobfuscate(y uint64) uint64 {
 		if i%2 == 0 {
 			// Inverse of even forward round: previous Rp=rBits, Lp=lBits
 			Rp := L // rBits
-			fn := f.simpleFbits(Rp, f.rBits, f.lBits, i)
+			fn := f.simpleFbits(Rp, i)
 			Lp := (R ^ fn) & lMask
 			L, R = Lp, Rp
 		} else {
 			// Inverse of odd forward round: previous Rp=lBits, Lp=rBits
 			Rp := L // lBits
-			fn := f.simpleFbits(Rp, f.lBits, f.rBits, i)
+			fn := f.simpleFbits(Rp, i)
 			Lp := (R ^ fn) & rMask
 			L, R = Lp, Rp
 		}
@@ -110,3 +165,11 @@ func (f *feistel) Deobfuscate(y uint64) uint64 {
 	// Pack original arrangement: L=lBits (low), R=rBits (high)
 	return (R << f.lBits) | (L & lMask)
 }
+
+// Describe implements Describer. Keyed instances do not reveal their key.
+func (f *feistel) Describe() string {
+	if f.keyed {
+		return fmt.Sprintf("feistel(k=%d,rounds=%d,keyed)", f.k, f.rounds)
+	}
+	return fmt.Sprintf("feistel(k=%d,rounds=%d)", f.k, f.rounds)
+}
@@ -0,0 +1,103 @@
+package idgen
+
+import "time"
+
+// Match is an ID found in free text by FindAll.
+type Match struct {
+	Offset  int       // byte offset of the ID within the text
+	ID      string    // the ID as it appears in the text
+	Raw     int64     // decoded raw tick
+	Time    time.Time // UTC timestamp of the raw tick; zero if Derived
+	Derived bool      // produced by FromKey; see WithDerivedIDs
+}
+
+// FindAll scans text for substrings laid out exactly like Format output
+// (configured width and dash grouping, either letter case) and returns them in
+// order of appearance. Candidates must stand alone: they may not be glued to
+// neighbouring letters or digits, or continue into further dash-separated
+// groups. Only canonical encodings, those Format could have produced, match.
+func (g *Generator) FindAll(text string) []Match {
+	n := g.formattedLen()
+	var matches []Match
+	buf := make([]byte, 0, n)
+	for i := 0; i+n <= len(text); i++ {
+		if !isAlnum(text[i]) || continuesToken(text, i-1, -1) {
+			continue
+		}
+		end := i + n
+		if !g.matchesLayout(text[i:end]) || continuesToken(text, end, 1) {
+			continue
+		}
+		id := text[i:end]
+		raw, err := g.Parse(id)
+		if err != nil {
+			continue
+		}
+		buf = g.AppendFormat(buf[:0], raw)
+		if !equalFoldID(buf, id) {
+			continue
+		}
+		m := Match{Offset: i, ID: id, Raw: raw, Derived: g.IsDerived(raw)}
+		if !m.Derived {
+			m.Time = g.TimestampFromRaw(raw)
+		}
+		matches = append(matches, m)
+		i = end - 1
+	}
+	return matches
+}
+
+// formattedLen returns the length of Format output, including dashes.
+func (g *Generator) formattedLen() int {
+	if g.width <= 4 {
+		return g.width
+	}
+	return g.width + (g.width-1)/4
+}
+
+// matchesLayout reports whether s has base36 characters with dashes exactly
+// where Format places them.
+func (g *Generator) matchesLayout(s string) bool {
+	grouped := g.width > 4
+	for i := 0; i < len(s); i++ {
+		if grouped && i%5 == 4 {
+			if s[i] != '-' {
+				return false
+			}
+		} else if !isAlnum(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// continuesToken reports whether text at position i (scanning in direction
+// dir) extends a candidate: either a letter/digit, or a dash followed by one.
+func continuesToken(text string, i, dir int) bool {
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	if isAlnum(text[i]) {
+		return true
+	}
+	j := i + dir
+	return text[i] == '-' && j >= 0 && j < len(text) && isAlnum(text[j])
+}
+
+func isAlnum(c byte) bool {
+	return base36Digit[c] != 0xff
+}
+
+// equalFoldID compares a formatted (lowercase) ID with s, ignoring case.
+// OR-ing 0x20 lowercases ASCII letters and leaves digits and '-' unchanged.
+func equalFoldID(formatted []byte, s string) bool {
+	if len(formatted) != len(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i]|0x20 != formatted[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindAll(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	r1 := g.Generate()
+	r2 := g.Generate()
+	id1, id2 := g.Format(r1), g.Format(r2)
+	text := "Hi, order " + id1 + " failed; retry as (" + strings.ToUpper(id2) + ").\n" +
+		"Not IDs: x" + id1 + ", " + id1 + "-abcd, " + strings.ReplaceAll(id2, "-", "") + "."
+
+	got := g.FindAll(text)
+	if len(got) != 2 {
+		t.Fatalf("FindAll found %d matches, want 2: %+v", len(got), got)
+	}
+	for i, want := range []struct {
+		raw int64
+		id  string
+	}{{r1, id1}, {r2, strings.ToUpper(id2)}} {
+		m := got[i]
+		if m.Raw != want.raw || m.ID != want.id {
+			t.Fatalf("match %d = %+v, want raw=%d id=%q", i, m, want.raw, want.id)
+		}
+		if text[m.Offset:m.Offset+len(m.ID)] != m.ID {
+			t.Fatalf("match %d offset %d does not point at %q", i, m.Offset, m.ID)
+		}
+		if !m.Time.Equal(g.TimestampFromRaw(want.raw)) {
+			t.Fatalf("match %d time = %v, want %v", i, m.Time, g.TimestampFromRaw(want.raw))
+		}
+	}
+}
+
+func TestFindAllRejectsNonCanonical(t *testing.T) {
+	// 2 bits in width 1: only codes 0-3 are canonical.
+	g, err := New(WithWidth(1), WithBits(2), WithEpoch(time.Now()), WithPace(time.Hour))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	got := g.FindAll("a 1 z 3 b")
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Fatalf("FindAll = %+v, want matches for 1 and 3", got)
+	}
+}
@@ -0,0 +1,58 @@
+package idgen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Describer is optionally implemented by an Obfuscator to describe its
+// parameters for Fingerprint. Descriptions may contain secret material such as
+// keys: Fingerprint only ever exposes a hash of them.
+type Describer interface {
+	Describe() string
+}
+
+// fingerprintProbes are fixed inputs whose obfuscated outputs are hashed into
+// the fingerprint, so obfuscators without Describe are still distinguished.
+var fingerprintProbes = [...]uint64{0, 1, 2, 3, 0x55, 0xAA, 0x1234, 0xFFFF, 0x9E3779B9, 0x12345678, 0xDEADBEEF, 0x7FFFFFFF, 1 << 36, 1<<40 + 7, 1 << 50, 1<<62 + 3}
+
+// Fingerprint returns a stable hash of everything that affects how IDs are
+// encoded and decoded: epoch, pace, width, bits, layout, random bits, HLC
+// counter bits, the derived-ID bit, the codec (alphabet with dash grouping),
+// and the obfuscator (its Describe output, if any, plus its outputs for fixed
+// probe inputs). Generators with equal fingerprints produce and parse each other's IDs
+// identically. The blocklist, observers, loggers, entropy source and layout
+// field values do not contribute since they never change how an ID decodes.
+func (g *Generator) Fingerprint() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "idgen/v1;epoch=%d;pace=%d;width=%d;bits=%d;codec=%s-g4", g.epochMS, g.pace, g.width, g.bits, g.alpha.name)
+	if g.rp != nil {
+		fmt.Fprintf(&b, ";range=%d", g.rp.n)
+	}
+	if g.layout != nil {
+		fmt.Fprintf(&b, ";layout=%s", g.layout)
+	}
+	if g.randBits > 0 {
+		fmt.Fprintf(&b, ";rand=%d", g.randBits)
+	}
+	if g.hlc {
+		fmt.Fprintf(&b, ";hlc=%d", g.hlcBits)
+	}
+	if g.derived {
+		b.WriteString(";derived")
+	}
+	if d, ok := g.ob.(Describer); ok {
+		fmt.Fprintf(&b, ";ob=%s", d.Describe())
+	}
+	h := sha256.New()
+	h.Write([]byte(b.String()))
+	var buf [8]byte
+	for _, x := range fingerprintProbes {
+		binary.BigEndian.PutUint64(buf[:], g.obfuscate(int64(x)))
+		h.Write(buf[:])
+	}
+	return "v1-" + hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,51 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprintStableAndSensitive(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// Pinned so an accidental change to the encoding is caught.
+	const want = "v1-c0fa969cb171b8e5"
+	if got := g.Fingerprint(); got != want {
+		t.Fatalf("default Fingerprint() = %q, want %q", got, want)
+	}
+	same, _ := NewFromConfig(DefaultConfig(), WithBlocklist([]string{"abc"}))
+	if same.Fingerprint() != want {
+		t.Fatalf("equivalent generator fingerprint %q differs", same.Fingerprint())
+	}
+
+	xa, _ := NewXorMask(41, 0x1234)
+	xb, _ := NewXorMask(41, 0x4321)
+	custom := struct{ Obfuscator }{xa} // hides Describe; probes still distinguish
+	variants := map[string][]Option{
+		"epoch":     {WithEpoch(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+		"pace":      {WithPace(2 * time.Millisecond)},
+		"width":     {WithWidth(9), WithBits(41)},
+		"rounds":    {withRounds(6)},
+		"fullwidth": {WithFullWidth()},
+		"xor-a":     {WithObfuscation(xa)},
+		"xor-b":     {WithObfuscation(xb)},
+	}
+	seen := map[string]string{want: "default"}
+	for name, opts := range variants {
+		v, err := New(opts...)
+		if err != nil {
+			t.Fatalf("%s: New error: %v", name, err)
+		}
+		fp := v.Fingerprint()
+		if prev, dup := seen[fp]; dup {
+			t.Fatalf("%s fingerprint %q collides with %s", name, fp, prev)
+		}
+		seen[fp] = name
+	}
+	hidden, _ := New(WithObfuscation(custom))
+	if prev, dup := seen[hidden.Fingerprint()]; dup {
+		t.Fatalf("obfuscator without Describe collides with %s", prev)
+	}
+}
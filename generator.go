@@ -1,11 +1,18 @@
 package idgen
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"errors"
-	"math"
-	"strconv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/bits"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,10 +31,42 @@ type Generator struct {
 	bits    uint
 	width   int
 	ob      Obfuscator
+	rp      *RangePermutation // optional; replaces the 2^bits domain with [0, rp.Size())
+	rounds  int               // rounds for the default Feistel obfuscator
+
+	fullWidth bool
+	alpha     *alphabet // digits for Format and Parse; see WithVowelFreeAlphabet
+	blocklist []string  // lowercase words Generate must not emit
+
+	// plausible window for Suggest; zero values default to [epoch, now]
+	plausibleFrom time.Time
+	plausibleTo   time.Time
+
+	obs         Observer     // optional instrumentation hooks
+	logger      *slog.Logger // optional; see WithLogger
+	horizonTick int64        // largest tick that encodes without wrapping
+
+	layout      *Layout // optional bit-field layout of raw values
+	fieldValues Fields  // static values for non-tick layout fields
+	tickShift   uint    // position of the tick field within raw values
+	staticBits  int64   // precomposed non-tick fields
+
+	randBits      uint        // low bits filled from entropy; see WithRandomBits
+	entropy       io.Reader   // source for randBits, crypto/rand by default
+	entropyFailed atomic.Bool // a custom entropy source has returned an error
+
+	derived bool // top raw bit marks FromKey IDs; see WithDerivedIDs
+
+	strict   bool       // see WithStrictOrdering
+	strictMu sync.Mutex // serializes Generate and NextN in strict mode
+
+	hlc      bool             // hybrid logical clock mode; see WithHLC
+	hlcBits  uint             // logical counter bits below the tick in HLC mode
+	maxDrift time.Duration    // largest accepted lead of observed IDs
+	now      func() time.Time // wall clock; see WithClock
 
 	// internal state
-	mu       sync.Mutex
-	lastTick int64
+	lastTick atomic.Int64 // in HLC mode, the tick followed by hlcBits counter bits
 }
 
 // Option configures a Generator.
@@ -85,6 +124,165 @@ func WithObfuscation(ob Obfuscator) Option {
 	}
 }
 
+// WithRangePermutation permutes raw values over [0, rp.Size()) instead of
+// [0, 2^bits). The range must fit in the configured width.
+func WithRangePermutation(rp *RangePermutation) Option {
+	return func(g *Generator) error {
+		if rp == nil {
+			return errors.New("range permutation cannot be nil")
+		}
+		g.rp = rp
+		return nil
+	}
+}
+
+// WithFullWidth makes every code of the configured width reachable by
+// cycle-walking over [0, 36^width) (31^width with WithVowelFreeAlphabet). A custom obfuscator set via
+// WithObfuscation is used as the base permutation; otherwise Feistel(k, 4)
+// with the smallest k covering the range is used. Width must be <= 12.
+func WithFullWidth() Option {
+	return func(g *Generator) error {
+		g.fullWidth = true
+		return nil
+	}
+}
+
+// WithVowelFreeAlphabet makes Format and Parse use the 31 characters 0-9 and
+// b-z without the vowels a, e, i, o and u, so IDs cannot spell words. Each
+// character holds fewer bits than base36: bits derived from width shrink
+// accordingly (39 instead of 41 at width 8). IDs are not interchangeable with
+// base36 ones.
+func WithVowelFreeAlphabet() Option {
+	return func(g *Generator) error {
+		g.alpha = alphabetVowelFree
+		return nil
+	}
+}
+
+// WithBlocklist makes Generate skip ticks whose formatted ID (ignoring dashes)
+// contains any of the given words, matched case-insensitively. Words must be
+// at least 2 base36 characters. Parse and Format are unaffected, so previously
+// issued IDs keep decoding. See DefaultBlocklist for a built-in English list.
+func WithBlocklist(words []string) Option {
+	return func(g *Generator) error {
+		list := make([]string, 0, len(words))
+		for _, w := range words {
+			w = strings.ToLower(w)
+			if err := checkBlockWord(w); err != nil {
+				return err
+			}
+			list = append(list, w)
+		}
+		g.blocklist = list
+		return nil
+	}
+}
+
+// WithLayout splits raw values into the named bit fields of l. The tick
+// occupies the first field and other fields take the values given with
+// WithFieldValues (zero by default). If bits are not set they are taken from
+// the layout. Layouts cannot be combined with range permutations.
+func WithLayout(l *Layout) Option {
+	return func(g *Generator) error {
+		if l == nil {
+			return errors.New("layout cannot be nil")
+		}
+		g.layout = l
+		return nil
+	}
+}
+
+// WithFieldValues sets the values Generate places in non-tick layout fields,
+// such as a node identifier.
+func WithFieldValues(values Fields) Option {
+	return func(g *Generator) error {
+		g.fieldValues = make(Fields, len(values))
+		for k, v := range values {
+			g.fieldValues[k] = v
+		}
+		return nil
+	}
+}
+
+// WithRandomBits fills the low n bits of every raw value with random bits
+// before obfuscation, so issued IDs cannot be enumerated from the tick alone.
+// The tick keeps the remaining bits (shortening the horizon accordingly) and
+// TimestampFromRaw ignores the random part. Use Split or Decode to separate
+// the two. Random bits cannot be combined with layouts, sharding or range
+// permutations.
+func WithRandomBits(n uint) Option {
+	return func(g *Generator) error {
+		if n == 0 || n > 62 {
+			return errors.New("random bits must be in [1,62]")
+		}
+		g.randBits = n
+		return nil
+	}
+}
+
+// WithEntropySource replaces crypto/rand as the source for WithRandomBits.
+// If r returns an error or a short read, Generate falls back to crypto/rand
+// for that ID and logs the first failure (see WithLogger).
+func WithEntropySource(r io.Reader) Option {
+	return func(g *Generator) error {
+		if r == nil {
+			return errors.New("entropy source cannot be nil")
+		}
+		g.entropy = r
+		return nil
+	}
+}
+
+// WithStrictOrdering extends the ordering guarantee of Generate to the paths
+// that hand out values generated earlier or elsewhere: NextN batches are
+// issued as one contiguous run, Sharded compares across shards so a later
+// call never returns a smaller value, and NewPooledSource discards buffered
+// IDs once the generator has issued newer ones directly. Generation is then
+// serialized, trading throughput for ordering.
+func WithStrictOrdering() Option {
+	return func(g *Generator) error {
+		g.strict = true
+		return nil
+	}
+}
+
+// WithObserver registers hooks that are notified about generation, waits,
+// clock regressions and horizon exhaustion.
+func WithObserver(obs Observer) Option {
+	return func(g *Generator) error {
+		if obs == nil {
+			return errors.New("observer cannot be nil")
+		}
+		g.obs = obs
+		return nil
+	}
+}
+
+// WithLogger reports clock regressions, long waits, horizon exhaustion and a
+// near-horizon warning at construction through l.
+func WithLogger(l *slog.Logger) Option {
+	return func(g *Generator) error {
+		if l == nil {
+			return errors.New("logger cannot be nil")
+		}
+		g.logger = l
+		return nil
+	}
+}
+
+// WithPlausibleWindow bounds the timestamps Suggest accepts for corrected IDs.
+// A zero earliest or latest defaults to the epoch or the current time respectively.
+func WithPlausibleWindow(earliest, latest time.Time) Option {
+	return func(g *Generator) error {
+		if !earliest.IsZero() && !latest.IsZero() && latest.Before(earliest) {
+			return errors.New("plausible window ends before it starts")
+		}
+		g.plausibleFrom = earliest
+		g.plausibleTo = latest
+		return nil
+	}
+}
+
 // New constructs a new Generator with provided options and sensible defaults:
 // - epoch: 2025-01-01T00:00:00Z
 // - pace: 1ms
@@ -97,29 +295,85 @@ func New(opts ...Option) (*Generator, error) {
 		pace:    time.Millisecond,
 		bits:    0, // derive from width if 0
 		width:   8, // default width
+		rounds:  4,
+		now:     time.Now,
+		alpha:   alphabet36,
 	}
 	for _, opt := range opts {
 		if err := opt(g); err != nil {
 			return nil, err
 		}
 	}
+	if g.fullWidth && g.rp == nil {
+		n, ok := g.alpha.pow(g.width)
+		if !ok {
+			return nil, errors.New("width too large for full-width range")
+		}
+		base := g.ob
+		if base == nil {
+			ob, err := NewFeistel(uint(bits.Len64(n-1)), g.rounds)
+			if err != nil {
+				return nil, err
+			}
+			base = ob
+		}
+		rp, err := NewRangePermutation(n, base)
+		if err != nil {
+			return nil, err
+		}
+		g.rp = rp
+	}
+	if g.rp != nil {
+		if g.layout != nil {
+			return nil, errors.New("layout cannot be combined with a range permutation")
+		}
+		if g.randBits > 0 {
+			return nil, errors.New("random bits cannot be combined with a range permutation")
+		}
+		if g.derived {
+			return nil, errors.New("derived IDs cannot be combined with a range permutation")
+		}
+		if g.hlc {
+			return nil, errors.New("hlc cannot be combined with a range permutation")
+		}
+		if n, ok := g.alpha.pow(g.width); ok && g.rp.Size() > n {
+			return nil, errors.New("width too small for range permutation")
+		}
+		if g.ob != nil && g.ob != g.rp.base {
+			return nil, errors.New("range permutation and obfuscator are mutually exclusive")
+		}
+		if g.bits != 0 && g.bits != g.rp.base.DomainBits() {
+			return nil, errors.New("obfuscator domain bits mismatch")
+		}
+		g.bits = g.rp.base.DomainBits()
+		g.ob = g.rp.base
+		g.horizonTick = int64(g.rp.n - 1)
+		if err := g.checkHorizon(g.horizonTick); err != nil {
+			return nil, err
+		}
+		g.attachLogger()
+		return g, nil
+	}
+	if g.layout != nil && g.bits == 0 {
+		g.bits = g.layout.bits
+	}
 	// Derive bits from width if not set
 	if g.bits == 0 {
-		// bits = floor(log2(36^width)) = floor(width * log2(36))
-		// log2(36) ≈ 5.16992500144
-		bits := uint(math.Floor(float64(g.width) * 5.16992500144))
+		// bits = floor(log2(36^width)) = floor(width * log2(36)), or
+		// log2(31) per character with the vowel-free alphabet
+		bits := g.alpha.maxBits(g.width)
 		if bits < 1 {
 			bits = 1
 		}
 		g.bits = bits
 	}
 	// Ensure width is enough to hold bits (36^width >= 2^bits)
-	if !widthSupportsBits(g.width, g.bits) {
+	if !g.alpha.holds(g.width, g.bits) {
 		return nil, errors.New("width too small for selected bits")
 	}
 	// Default obfuscator if not provided
 	if g.ob == nil {
-		ob, err := NewFeistel(g.bits, 4)
+		ob, err := NewFeistel(g.bits, g.rounds)
 		if err != nil {
 			return nil, err
 		}
@@ -127,17 +381,153 @@ func New(opts ...Option) (*Generator, error) {
 	} else if g.ob.DomainBits() != g.bits {
 		return nil, errors.New("obfuscator domain bits mismatch")
 	}
+	if err := g.setupLayout(); err != nil {
+		return nil, err
+	}
+	if err := g.setupRandom(); err != nil {
+		return nil, err
+	}
+	if err := g.setupHLC(); err != nil {
+		return nil, err
+	}
+	tickBits := g.bits - g.tickShift
+	if g.derived {
+		if tickBits < 2 {
+			return nil, errors.New("derived IDs need at least 2 tick bits")
+		}
+		tickBits--
+	}
+	g.horizonTick = int64(uint64(1)<<tickBits - 1)
+	if err := g.checkHorizon(g.horizonTick); err != nil {
+		return nil, err
+	}
+	g.attachLogger()
 	return g, nil
 }
 
+// checkHorizon rejects configurations whose current tick already wraps, given
+// the largest tick that encodes without wrapping.
+func (g *Generator) checkHorizon(horizonTick int64) error {
+	if h := g.tickTime(horizonTick); h.Before(g.now()) {
+		return fmt.Errorf("horizon %s has already passed; widen the id, use fewer random or non-tick bits, or move the epoch",
+			h.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// setupRandom validates WithRandomBits and reserves the low bits below the tick.
+func (g *Generator) setupRandom() error {
+	if g.randBits == 0 {
+		return nil
+	}
+	if g.layout != nil {
+		return errors.New("random bits cannot be combined with a layout")
+	}
+	if g.randBits >= g.bits {
+		return fmt.Errorf("random bits %d leave no room for the tick in %d bits", g.randBits, g.bits)
+	}
+	if g.entropy == nil {
+		g.entropy = rand.Reader
+	}
+	g.tickShift = g.randBits
+	return nil
+}
+
+// random reads randBits bits from the entropy source, falling back to
+// crypto/rand if a custom source fails.
+func (g *Generator) random() int64 {
+	var b [8]byte
+	if _, err := io.ReadFull(g.entropy, b[:]); err != nil {
+		if !g.entropyFailed.Swap(true) && g.logger != nil {
+			g.logger.LogAttrs(context.Background(), slog.LevelWarn, "idgen: entropy source failed, using crypto/rand", slog.Any("error", err))
+		}
+		rand.Read(b[:]) // never returns an error
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]) & (uint64(1)<<g.randBits - 1))
+}
+
+// Split separates a raw value into its tick and random bits (see
+// WithRandomBits). Without random bits the random part is zero. With a Layout,
+// tick is the tick field; use Decode for the other fields.
+func (g *Generator) Split(raw int64) (tick int64, random uint64) {
+	return raw >> g.tickShift, uint64(raw) & (uint64(1)<<g.randBits - 1)
+}
+
 func widthSupportsBits(width int, bits uint) bool {
-	// Compare using logs to avoid big ints: width*log2(36) >= bits
-	return float64(width)*5.16992500144+1e-12 >= float64(bits)
+	return alphabet36.holds(width, bits)
+}
+
+// checkBlockWord validates a lowercase blocklist word.
+func checkBlockWord(w string) error {
+	if len(w) < 2 {
+		return fmt.Errorf("blocklist word %q too short", w)
+	}
+	for i := 0; i < len(w); i++ {
+		if !isAlnum(w[i]) {
+			return fmt.Errorf("blocklist word %q is not base36", w)
+		}
+	}
+	return nil
+}
+
+// blocked reports whether the formatted ID for raw contains a blocklisted word.
+func (g *Generator) blocked(raw int64) bool {
+	if len(g.blocklist) == 0 {
+		return false
+	}
+	plain := strings.ReplaceAll(g.Format(raw), "-", "")
+	for _, w := range g.blocklist {
+		if strings.Contains(plain, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// obfuscate maps a raw value into the code space using the configured permutation.
+func (g *Generator) obfuscate(raw int64) uint64 {
+	if g.rp != nil {
+		return g.rp.Obfuscate(uint64(raw) % g.rp.n)
+	}
+	mask := uint64((uint64(1) << g.bits) - 1)
+	return g.ob.Obfuscate(uint64(raw) & mask)
+}
+
+// deobfuscate reverses obfuscate, rejecting codes outside the permuted range.
+func (g *Generator) deobfuscate(v uint64) (int64, error) {
+	if g.rp != nil {
+		if v >= g.rp.n {
+			return 0, errors.New("id out of range")
+		}
+		return int64(g.rp.Deobfuscate(v)), nil
+	}
+	mask := uint64((uint64(1) << g.bits) - 1)
+	v &= mask
+	return int64(g.ob.Deobfuscate(v) & mask), nil
 }
 
 // Generate returns a raw tick count (int64) since epoch in units of pace.
+// With a Layout, the tick is placed in its field alongside the static field values.
 // It enforces monotonicity and the configured minimum spacing.
+// The hot path is lock-free: concurrent callers race a compare-and-swap on the
+// last issued tick, and only the winner of a given tick returns it.
+// Ticks whose formatted ID contains a blocklisted word are consumed and skipped.
+//
+// Generate is linearizable: if one call returns before another starts, even on
+// a different goroutine, the later call returns a strictly greater value.
+// In HLC mode (see WithHLC) it also exceeds every observed ID.
+//
+// Generate sleeps in real time until the clock (see WithClock) moves past the
+// last issued tick, so a clock that does not advance blocks it.
 func (g *Generator) Generate() int64 {
+	if g.strict {
+		g.strictMu.Lock()
+		defer g.strictMu.Unlock()
+	}
+	return g.generate()
+}
+
+func (g *Generator) generate() int64 {
 	q := g.pace
 	if q <= 0 {
 		q = time.Millisecond
@@ -147,69 +537,137 @@ func (g *Generator) Generate() int64 {
 		// support sub-ms by rounding up to 1ms granularity for now
 		d = 1
 	}
+	var waitStart time.Time
+	regressed := false
 	for {
-		nowTick := (time.Now().UnixMilli() - g.epochMS) / int64(d)
-		g.mu.Lock()
-		if nowTick > g.lastTick {
-			g.lastTick = nowTick
-			g.mu.Unlock()
-			return nowTick
-		}
-		g.mu.Unlock()
+		nowTick := (g.now().UnixMilli() - g.epochMS) / int64(d)
+		next := nowTick
+		last := g.lastTick.Load()
+		if g.hlc {
+			// Issue the wall tick with a zero counter once the clock has passed
+			// the last value, else bump the counter while it fits in the tick.
+			next = nowTick << g.hlcBits
+			if next <= last && (last+1)>>g.hlcBits == last>>g.hlcBits {
+				next = last + 1
+			}
+		}
+		if next > last {
+			if g.lastTick.CompareAndSwap(last, next) {
+				raw := next<<(g.tickShift-g.hlcBits) | g.staticBits
+				if g.randBits > 0 {
+					raw |= g.random()
+				}
+				if g.blocked(raw) {
+					continue
+				}
+				if g.obs != nil {
+					g.observe(next>>g.hlcBits, waitStart)
+				}
+				return raw
+			}
+			// Lost the race to another caller; re-read the clock and retry.
+			continue
+		}
+		// In HLC mode a clock behind the last value is expected after Observe.
+		if !g.hlc && nowTick < last && !regressed && g.obs != nil {
+			// The wall clock stepped backwards past an issued tick; we wait it out.
+			regressed = true
+			g.obs.ClockRegression(time.Duration(last-nowTick) * q)
+		}
+		if waitStart.IsZero() {
+			waitStart = g.now()
+		}
 		time.Sleep(q)
 	}
 }
 
-// Format converts a raw tick into a fixed-width lowercase base36 string using the obfuscator.
+// observe reports a successful Generate to the observer.
+func (g *Generator) observe(tick int64, waitStart time.Time) {
+	if !waitStart.IsZero() {
+		g.obs.Waited(g.now().Sub(waitStart))
+	}
+	if tick > g.horizonTick {
+		g.obs.Exhausted()
+	}
+	g.obs.Generated()
+}
+
+// Format converts a raw tick into a fixed-width lowercase base36 string (or
+// the alphabet set by WithVowelFreeAlphabet) using the obfuscator.
 // The returned human-readable string is grouped into chunks of 4 characters separated by '-'.
 func (g *Generator) Format(raw int64) string {
-	mask := uint64((uint64(1) << g.bits) - 1)
-	v := uint64(raw) & mask
-	obf := g.ob.Obfuscate(v)
-	s := strconv.FormatUint(obf, 36)
-	if len(s) < g.width {
-		var b strings.Builder
-		for i := 0; i < g.width-len(s); i++ {
-			b.WriteByte('0')
-		}
-		b.WriteString(s)
-		s = b.String()
-	}
-	// Insert dashes every 4 characters for readability
-	if len(s) > 4 {
-		var b strings.Builder
-		b.Grow(len(s) + len(s)/4) // rough estimate
-		for i := 0; i < len(s); i++ {
-			if i > 0 && i%4 == 0 {
-				b.WriteByte('-')
-			}
-			b.WriteByte(s[i])
+	n := g.width
+	if n < 13 {
+		n = 13 // longest base31 or base36 uint64
+	}
+	return string(g.AppendFormat(make([]byte, 0, n+n/4), raw))
+}
+
+// AppendFormat appends the formatted form of raw (as produced by Format) to dst
+// and returns the extended buffer. It does not allocate when dst has enough capacity.
+func (g *Generator) AppendFormat(dst []byte, raw int64) []byte {
+	var digits [13]byte // longest base31 or base36 uint64
+	d := g.alpha.appendUint(digits[:0], g.obfuscate(raw))
+	n := len(d)
+	if n < g.width {
+		n = g.width
+	}
+	pad := n - len(d)
+	for i := 0; i < n; i++ {
+		// Insert dashes every 4 characters for readability
+		if i > 0 && i%4 == 0 {
+			dst = append(dst, '-')
+		}
+		if i < pad {
+			dst = append(dst, '0')
+		} else {
+			dst = append(dst, d[i-pad])
 		}
-		s = b.String()
 	}
-	return s
+	return dst
 }
 
 // Parse reverses Format and returns the raw tick value.
+// Surrounding whitespace and '-' separators are ignored; letters may be either case.
 func (g *Generator) Parse(s string) (int64, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0, errors.New("empty id")
+	v, err := decodeDigits(g.alpha, strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
 	}
-	// Remove optional dash separators used in human-readable format
-	s = strings.ReplaceAll(s, "-", "")
-	v, err := strconv.ParseUint(s, 36, 64)
+	return g.deobfuscate(v)
+}
+
+// ParseBytes is like Parse but decodes directly from a byte slice without allocating.
+func (g *Generator) ParseBytes(b []byte) (int64, error) {
+	v, err := decodeDigits(g.alpha, bytes.TrimSpace(b))
 	if err != nil {
 		return 0, err
 	}
-	mask := uint64((uint64(1) << g.bits) - 1)
-	v &= mask
-	raw := g.ob.Deobfuscate(v) & mask
-	return int64(raw), nil
+	return g.deobfuscate(v)
+}
+
+var (
+	errEmptyID    = errors.New("empty id")
+	errInvalidID  = errors.New("invalid character in id")
+	errIDOverflow = errors.New("id value out of range")
+)
+
+// base36Digit maps an ASCII byte to its base36 value, or 0xff if it is not a digit.
+var base36Digit = alphabet36.values
+
+// decodeBase36 decodes an already trimmed base36 id, skipping '-' separators.
+func decodeBase36[T ~string | ~[]byte](s T) (uint64, error) {
+	return decodeDigits(alphabet36, s)
 }
 
 // TimestampFromRaw converts a raw tick to time.Time in UTC.
+// With a Layout or random bits, only the tick is used.
 func (g *Generator) TimestampFromRaw(raw int64) time.Time {
+	return g.tickTime(raw >> g.tickShift)
+}
+
+// tickTime converts a bare tick (without other layout fields) to UTC time.
+func (g *Generator) tickTime(tick int64) time.Time {
 	q := g.pace
 	if q <= 0 {
 		q = time.Millisecond
@@ -218,15 +676,41 @@ func (g *Generator) TimestampFromRaw(raw int64) time.Time {
 	if d <= 0 {
 		d = 1
 	}
-	ms := g.epochMS + raw*int64(d)
+	ms := g.epochMS + tick*int64(d)
 	return time.UnixMilli(ms).UTC()
 }
 
+// Epoch returns the configured epoch in UTC.
+func (g *Generator) Epoch() time.Time { return time.UnixMilli(g.epochMS).UTC() }
+
+// Pace returns the minimum spacing between generated IDs.
+func (g *Generator) Pace() time.Duration { return g.pace }
+
+// Width returns the fixed base36 width of formatted IDs (excluding dashes).
+func (g *Generator) Width() int { return g.width }
+
+// Bits returns the obfuscation domain size in bits.
+func (g *Generator) Bits() uint { return g.bits }
+
+// RandomBits returns the number of random low bits set by WithRandomBits.
+func (g *Generator) RandomBits() uint { return g.randBits }
+
+// Horizon returns the UTC timestamp of the last tick that encodes without
+// wrapping around the domain. Generate keeps issuing ticks past it, but their
+// IDs collide with earlier ones; observers are notified via Exhausted.
+func (g *Generator) Horizon() time.Time {
+	return g.tickTime(g.horizonTick)
+}
+
 // TimestampFromID parses a formatted ID and returns the UTC timestamp.
+// IDs produced by FromKey are rejected with ErrDerivedID.
 func (g *Generator) TimestampFromID(id string) (time.Time, error) {
 	raw, err := g.Parse(id)
 	if err != nil {
 		return time.Time{}, err
 	}
+	if g.IsDerived(raw) {
+		return time.Time{}, ErrDerivedID
+	}
 	return g.TimestampFromRaw(raw), nil
 }
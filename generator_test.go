@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -198,3 +199,167 @@ func TestParseErrors(t *testing.T) {
 		t.Fatal("expected error on invalid base36 string")
 	}
 }
+
+// lockedGenerate is the previous mutex-guarded Generate loop, kept only as a
+// baseline for the contention benchmarks below.
+type lockedGenerate struct {
+	g        *Generator
+	mu       sync.Mutex
+	lastTick int64
+}
+
+func (l *lockedGenerate) Generate() int64 {
+	for {
+		nowTick := (time.Now().UnixMilli() - l.g.epochMS) / int64(l.g.pace/time.Millisecond)
+		l.mu.Lock()
+		if nowTick > l.lastTick {
+			l.lastTick = nowTick
+			l.mu.Unlock()
+			return nowTick
+		}
+		l.mu.Unlock()
+		time.Sleep(l.g.pace)
+	}
+}
+
+func benchmarkGenerateParallel(b *testing.B, gen func() int64) {
+	for _, procs := range []int{1, 16, 64} {
+		b.Run(strconv.Itoa(procs)+"x", func(b *testing.B) {
+			b.SetParallelism(procs)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					gen()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGenerateAtomic(b *testing.B) {
+	g, err := New()
+	if err != nil {
+		b.Fatalf("New error: %v", err)
+	}
+	benchmarkGenerateParallel(b, g.Generate)
+}
+
+func BenchmarkGenerateMutex(b *testing.B) {
+	g, err := New()
+	if err != nil {
+		b.Fatalf("New error: %v", err)
+	}
+	l := &lockedGenerate{g: g}
+	benchmarkGenerateParallel(b, l.Generate)
+}
+
+func TestAppendFormatMatchesFormatWithoutAllocs(t *testing.T) {
+	for _, width := range []int{3, 8, 14} {
+		g, err := New(WithWidth(width), WithBits(min(uint(width)*5, 41)), WithEpoch(time.Now()))
+		if err != nil {
+			t.Fatalf("New(width=%d) error: %v", width, err)
+		}
+		raw := g.Generate()
+		buf := []byte("id=")
+		if got, want := string(g.AppendFormat(buf, raw)), "id="+g.Format(raw); got != want {
+			t.Fatalf("width %d: AppendFormat=%q want %q", width, got, want)
+		}
+		dst := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = g.AppendFormat(dst[:0], raw)
+		})
+		if allocs != 0 {
+			t.Fatalf("width %d: AppendFormat allocs/op = %v, want 0", width, allocs)
+		}
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	g, err := New()
+	if err != nil {
+		b.Fatalf("New error: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = g.Format(int64(i))
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	g, err := New()
+	if err != nil {
+		b.Fatalf("New error: %v", err)
+	}
+	dst := make([]byte, 0, 32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = g.AppendFormat(dst[:0], int64(i))
+	}
+}
+
+func TestParseBytesMatchesParseWithoutAllocs(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	raw := g.Generate()
+	id := g.Format(raw)
+	for _, in := range []string{id, strings.ToUpper(id), "  " + id + "\n", strings.ReplaceAll(id, "-", ""), "", " - ", "!!!", "zzzzzzzzzzzzzzz"} {
+		want, wantErr := g.Parse(in)
+		got, gotErr := g.ParseBytes([]byte(in))
+		if got != want || gotErr != wantErr {
+			t.Fatalf("ParseBytes(%q) = %d, %v; Parse = %d, %v", in, got, gotErr, want, wantErr)
+		}
+	}
+	if back, _ := g.ParseBytes([]byte(id)); back != raw {
+		t.Fatalf("round-trip mismatch: got %d want %d", back, raw)
+	}
+	line := []byte("order " + id + " created")
+	field := line[6 : 6+len(id)]
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := g.ParseBytes(field); err != nil {
+			t.Fatalf("ParseBytes error: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("ParseBytes allocs/op = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	g, err := New()
+	if err != nil {
+		b.Fatalf("New error: %v", err)
+	}
+	id := []byte(g.Format(g.Generate()))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = g.ParseBytes(id)
+	}
+}
+
+func TestNewRejectsPassedHorizon(t *testing.T) {
+	l, _ := ParseLayout("tick:33,node:5,seq:3")
+	cases := map[string][]Option{
+		"random bits": {WithRandomBits(20)},
+		"layout":      {WithLayout(l)},
+		"derived":     {WithBits(34), WithDerivedIDs()},
+		"full width":  {WithWidth(4), WithFullWidth()},
+	}
+	for name, opts := range cases {
+		if _, err := New(opts...); err == nil || !strings.Contains(err.Error(), "horizon") {
+			t.Errorf("%s: New error = %v, want horizon error", name, err)
+		}
+	}
+	// 2^20 shards leave 21 tick bits at width 8.
+	if _, err := NewSharded(1 << 20); err == nil || !strings.Contains(err.Error(), "horizon") {
+		t.Errorf("sharded: NewSharded error = %v, want horizon error", err)
+	}
+	if err := (Config{RandomBits: 20}).Validate(); err == nil || !strings.Contains(err.Error(), "horizon") {
+		t.Errorf("Validate() = %v, want horizon error", err)
+	}
+	// The same layout is fine from a recent epoch.
+	if _, err := New(WithLayout(l), WithEpoch(time.Now())); err != nil {
+		t.Errorf("recent epoch: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithHLC turns the generator into a hybrid logical clock for causality
+// across services. The tick keeps following the wall clock, and counterBits
+// bits below it hold a logical counter: Generate issues the current tick with
+// a zero counter once the wall clock has passed the last issued value, and
+// otherwise increments the counter, so up to 2^counterBits IDs share a tick.
+// Observe raises the clock to an ID received from a peer, so the next
+// Generate returns a greater value without waiting for the wall clock.
+//
+// Ticks never run ahead of the later of the local wall clock and the largest
+// observed tick, and IDs decode to wall time again once the wall clock passes
+// that tick. When the counter of a tick is exhausted, Generate waits for the
+// wall clock to pass it, which after observing a peer that is ahead takes up
+// to that peer's lead. Observe rejects IDs more than maxDrift ahead of the
+// local clock, which bounds how far a misbehaving peer can push timestamps; 0
+// disables the check. The counter shortens the horizon by counterBits bits.
+// Not supported with layouts, range permutations or sharding.
+func WithHLC(maxDrift time.Duration, counterBits uint) Option {
+	return func(g *Generator) error {
+		if maxDrift < 0 {
+			return errors.New("max drift must be >= 0")
+		}
+		if counterBits < 1 || counterBits > 32 {
+			return errors.New("hlc counter bits must be in [1,32]")
+		}
+		g.hlc = true
+		g.hlcBits = counterBits
+		g.maxDrift = maxDrift
+		return nil
+	}
+}
+
+// setupHLC reserves the counter bits directly below the tick. Called by New
+// after setupRandom.
+func (g *Generator) setupHLC() error {
+	if !g.hlc {
+		return nil
+	}
+	if g.layout != nil {
+		return errors.New("hlc cannot be combined with a layout")
+	}
+	if g.tickShift+g.hlcBits >= g.bits {
+		return fmt.Errorf("hlc counter bits %d leave no room for the tick in %d bits", g.hlcBits, g.bits)
+	}
+	g.tickShift += g.hlcBits
+	return nil
+}
+
+// WithClock replaces time.Now as the wall clock read by New's horizon check,
+// by Generate (including the wait durations reported to observers) and by
+// Suggest's default window, for tests and simulations. The clock must keep
+// advancing: when the current tick is taken, Generate sleeps in real time and
+// reads the clock again, so a frozen clock blocks it.
+func WithClock(now func() time.Time) Option {
+	return func(g *Generator) error {
+		if now == nil {
+			return errors.New("clock cannot be nil")
+		}
+		g.now = now
+		return nil
+	}
+}
+
+// Observe advances the logical clock of an HLC generator (see WithHLC) to the
+// tick and counter of id, a formatted ID from a peer sharing this
+// configuration, so the next Generate returns a greater value.
+func (g *Generator) Observe(id string) error {
+	if !g.hlc {
+		return errors.New("observe requires WithHLC")
+	}
+	raw, err := g.Parse(id)
+	if err != nil {
+		return err
+	}
+	if g.IsDerived(raw) {
+		return ErrDerivedID
+	}
+	tick, _ := g.Split(raw)
+	if tick > g.horizonTick {
+		return errors.New("observed id is beyond the horizon")
+	}
+	if g.maxDrift > 0 {
+		if ahead := g.tickTime(tick).Sub(g.now()); ahead > g.maxDrift {
+			return fmt.Errorf("observed id is %s ahead of the local clock (max %s)", ahead, g.maxDrift)
+		}
+	}
+	clock := raw >> (g.tickShift - g.hlcBits)
+	for {
+		last := g.lastTick.Load()
+		if clock <= last || g.lastTick.CompareAndSwap(last, clock) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+// skewedClock returns the real clock shifted by skew, simulating a peer whose
+// wall clock is off.
+func skewedClock(skew time.Duration) func() time.Time {
+	return func() time.Time { return time.Now().Add(skew) }
+}
+
+func TestHLCObserveRespectsCausality(t *testing.T) {
+	fast, err := New(WithWidth(10), WithHLC(0, 8), WithClock(skewedClock(5*time.Second)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	slow, err := New(WithWidth(10), WithHLC(0, 8), WithClock(skewedClock(-5*time.Second)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if fast.Fingerprint() != slow.Fingerprint() {
+		t.Fatal("peers should share a fingerprint")
+	}
+
+	// Ping-pong a message between the peers; each reply must follow its cause.
+	prev := fast.Generate()
+	for i := 0; i < 10; i++ {
+		from, to := fast, slow
+		if i%2 == 1 {
+			from, to = slow, fast
+		}
+		if err := to.Observe(from.Format(prev)); err != nil {
+			t.Fatalf("Observe error: %v", err)
+		}
+		next := to.Generate()
+		if next <= prev {
+			t.Fatalf("round %d: generated %d after observing %d", i, next, prev)
+		}
+		prev = next
+	}
+
+	// The slow peer's ticks run no further ahead of real time than the fast
+	// peer's clock.
+	if lead, limit := time.Until(slow.TimestampFromRaw(slow.Generate())), 5*time.Second; lead > limit {
+		t.Fatalf("slow peer is %v ahead of real time, want at most %v", lead, limit)
+	}
+}
+
+func TestHLCPingPongLeadStaysBounded(t *testing.T) {
+	a, err := New(WithWidth(10), WithHLC(10*time.Millisecond, 4))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	b, _ := New(WithWidth(10), WithHLC(10*time.Millisecond, 4))
+
+	// Peers with the same clock bounce IDs far faster than one per pace, so
+	// the counter overflows many times; the ticks must still follow the wall
+	// clock instead of drifting ahead of it.
+	prev := a.Generate()
+	for i := 0; i < 2000; i++ {
+		from, to := a, b
+		if i%2 == 1 {
+			from, to = b, a
+		}
+		if err := to.Observe(from.Format(prev)); err != nil {
+			t.Fatalf("round %d: Observe error: %v", i, err)
+		}
+		next := to.Generate()
+		if next <= prev {
+			t.Fatalf("round %d: generated %d after observing %d", i, next, prev)
+		}
+		if ts := to.TimestampFromRaw(next); ts.After(time.Now()) {
+			t.Fatalf("round %d: tick %v is ahead of the wall clock", i, ts)
+		}
+		prev = next
+	}
+}
+
+func TestHLCLeadDecays(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	peer, _ := New(WithWidth(10), WithHLC(0, 8), WithClock(newManualClock(start.Add(time.Second)).now))
+	clock := newManualClock(start)
+	g, err := New(WithWidth(10), WithHLC(0, 8), WithClock(clock.now))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	observed := peer.Generate()
+	if err := g.Observe(peer.Format(observed)); err != nil {
+		t.Fatalf("Observe error: %v", err)
+	}
+
+	// While the wall clock lags, the observed tick is reused with a higher
+	// counter; nothing waits on the clock.
+	v := g.Generate()
+	f, _ := g.Decode(g.Format(v))
+	if tick, _ := g.Split(v); v <= observed || tick != 1000+start.UnixMilli()-g.epochMS || f[CounterField] != 1 {
+		t.Fatalf("after Observe: generated %d (fields %v), want the observed tick with counter 1", v, f)
+	}
+	if raw, err := g.Compose(f); err != nil || raw != v {
+		t.Fatalf("Compose(%v) = %d, %v; want %d", f, raw, err, v)
+	}
+
+	// Once the wall clock passes the observed tick, the lead is gone.
+	clock.advance(2 * time.Second)
+	v = g.Generate()
+	if ts := g.TimestampFromRaw(v); !ts.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("after the clock caught up: timestamp %v, want %v", ts, start.Add(2*time.Second))
+	}
+	if f, _ := g.Decode(g.Format(v)); f[CounterField] != 0 {
+		t.Fatalf("counter = %d, want 0 for a fresh tick", f[CounterField])
+	}
+}
+
+func TestHLCBurstStaysWithinObservedSkew(t *testing.T) {
+	const skew = 2 * time.Second
+	peer, _ := New(WithWidth(10), WithHLC(0, 8), WithClock(skewedClock(skew)))
+	g, err := New(WithWidth(10), WithHLC(5*time.Second, 8))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	observed := peer.Generate()
+	if err := g.Observe(peer.Format(observed)); err != nil {
+		t.Fatalf("Observe error: %v", err)
+	}
+	// Back-to-back local IDs share the observed tick through the counter, so
+	// the lead stays at the observed skew instead of growing with the burst.
+	prev := observed
+	for i := 0; i < 200; i++ {
+		v := g.Generate()
+		if v <= prev {
+			t.Fatalf("generated %d after %d", v, prev)
+		}
+		if lead := time.Until(g.TimestampFromRaw(v)); lead > skew {
+			t.Fatalf("ID %d is %v ahead of the clock, want at most %v", i, lead, skew)
+		}
+		prev = v
+	}
+	// A peer tolerating a bit more than the skew still accepts the burst.
+	checker, _ := New(WithWidth(10), WithHLC(skew+time.Second, 8))
+	if err := checker.Observe(g.Format(prev)); err != nil {
+		t.Fatalf("Observe of the burst's last ID: %v", err)
+	}
+}
+
+func TestHLCObserveValidation(t *testing.T) {
+	ahead, _ := New(WithWidth(10), WithHLC(0, 8), WithClock(skewedClock(time.Hour)))
+	g, err := New(WithWidth(10), WithHLC(time.Minute, 8))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if err := g.Observe(ahead.Format(ahead.Generate())); err == nil {
+		t.Fatal("expected drift error for an ID an hour ahead")
+	}
+	if err := g.Observe("not an id!"); err == nil {
+		t.Fatal("expected parse error")
+	}
+	plain, _ := New()
+	if err := plain.Observe(plain.Format(1)); err == nil {
+		t.Fatal("expected error without WithHLC")
+	}
+	if plain.Fingerprint() == g.Fingerprint() {
+		t.Fatal("the counter bits should change the fingerprint")
+	}
+	l, _ := ParseLayout("tick:38,node:8")
+	cases := map[string][]Option{
+		"no counter": {WithHLC(0, 0)},
+		"no tick":    {WithBits(20), WithHLC(0, 20)},
+		"layout":     {WithWidth(9), WithLayout(l), WithHLC(0, 4)},
+	}
+	for name, opts := range cases {
+		if _, err := New(opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := NewSharded(2, WithWidth(10), WithHLC(0, 8)); err == nil {
+		t.Fatal("expected error combining HLC with sharding")
+	}
+}
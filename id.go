@@ -0,0 +1,77 @@
+package idgen
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ID is a raw tick bound to the Generator that issued or parsed it, so it can
+// render and decode itself. The zero ID is not valid.
+type ID struct {
+	raw int64
+	g   *Generator
+}
+
+// GenerateID is like Generate but returns the tick as an ID.
+func (g *Generator) GenerateID() ID {
+	return ID{raw: g.Generate(), g: g}
+}
+
+// ParseID is like Parse but returns the decoded tick as an ID.
+func (g *Generator) ParseID(s string) (ID, error) {
+	raw, err := g.Parse(s)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{raw: raw, g: g}, nil
+}
+
+// Raw returns the raw tick.
+func (id ID) Raw() int64 { return id.raw }
+
+// IsZero reports whether id is the zero ID.
+func (id ID) IsZero() bool { return id.g == nil }
+
+// String returns the formatted ID, or "" for the zero ID.
+func (id ID) String() string {
+	if id.g == nil {
+		return ""
+	}
+	return id.g.Format(id.raw)
+}
+
+// IsDerived reports whether id was produced by FromKey rather than Generate.
+func (id ID) IsDerived() bool {
+	return id.g != nil && id.g.IsDerived(id.raw)
+}
+
+// Time returns the UTC timestamp of the tick, or the zero time for the zero ID
+// and for derived IDs, which carry no timestamp.
+func (id ID) Time() time.Time {
+	if id.g == nil || id.IsDerived() {
+		return time.Time{}
+	}
+	return id.g.TimestampFromRaw(id.raw)
+}
+
+// LogValue implements slog.LogValuer, rendering the ID as a group with its
+// formatted form, decoded creation time and any non-tick layout fields (such
+// as node). Derived IDs log derived=true in place of the creation time.
+func (id ID) LogValue() slog.Value {
+	if id.g == nil {
+		return slog.GroupValue()
+	}
+	attrs := []slog.Attr{slog.String("id", id.String())}
+	if id.IsDerived() {
+		attrs = append(attrs, slog.Bool("derived", true))
+	} else {
+		attrs = append(attrs, slog.Time("created_at", id.Time()))
+	}
+	if l := id.g.layout; l != nil {
+		values := l.Decompose(id.raw)
+		for _, f := range l.fields[1:] {
+			attrs = append(attrs, slog.Uint64(f.Name, values[f.Name]))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
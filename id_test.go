@@ -0,0 +1,44 @@
+package idgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestIDLogValue(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	id := g.GenerateID()
+	if id.IsZero() || id.String() != g.Format(id.Raw()) {
+		t.Fatalf("GenerateID() = %q, want Format(%d)", id, id.Raw())
+	}
+	back, err := g.ParseID(id.String())
+	if err != nil || back != id {
+		t.Fatalf("ParseID(%q) = %v, %v; want %v", id, back, err, id)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("created", "order", id)
+	var rec struct {
+		Order struct {
+			ID        string    `json:"id"`
+			CreatedAt time.Time `json:"created_at"`
+		} `json:"order"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if rec.Order.ID != id.String() || !rec.Order.CreatedAt.Equal(id.Time()) {
+		t.Fatalf("logged %+v, want id=%q created_at=%v", rec.Order, id, id.Time())
+	}
+
+	var zero ID
+	if zero.String() != "" || !zero.Time().IsZero() || len(zero.LogValue().Group()) != 0 {
+		t.Fatal("zero ID should render empty")
+	}
+}
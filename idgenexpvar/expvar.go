@@ -0,0 +1,54 @@
+// Package idgenexpvar publishes idgen.Observer callbacks as expvar counters.
+//
+//	obs := idgenexpvar.Publish("idgen")
+//	g, _ := idgen.New(idgen.WithObserver(obs))
+//
+// The counters then appear under "idgen" in /debug/vars:
+// generated, waits, wait_ns, clock_regressions and exhausted.
+package idgenexpvar
+
+import (
+	"expvar"
+	"time"
+)
+
+// Observer implements idgen.Observer on top of expvar integers.
+type Observer struct {
+	generated   expvar.Int
+	waits       expvar.Int
+	waitNanos   expvar.Int
+	regressions expvar.Int
+	exhausted   expvar.Int
+}
+
+// New returns an Observer whose counters are stored in m.
+func New(m *expvar.Map) *Observer {
+	o := &Observer{}
+	m.Set("generated", &o.generated)
+	m.Set("waits", &o.waits)
+	m.Set("wait_ns", &o.waitNanos)
+	m.Set("clock_regressions", &o.regressions)
+	m.Set("exhausted", &o.exhausted)
+	return o
+}
+
+// Publish creates a new top-level expvar map called name and returns an
+// Observer backed by it. Like expvar.NewMap, it panics if name is already in use.
+func Publish(name string) *Observer {
+	return New(expvar.NewMap(name))
+}
+
+// Generated implements idgen.Observer.
+func (o *Observer) Generated() { o.generated.Add(1) }
+
+// Waited implements idgen.Observer.
+func (o *Observer) Waited(d time.Duration) {
+	o.waits.Add(1)
+	o.waitNanos.Add(int64(d))
+}
+
+// ClockRegression implements idgen.Observer.
+func (o *Observer) ClockRegression(time.Duration) { o.regressions.Add(1) }
+
+// Exhausted implements idgen.Observer.
+func (o *Observer) Exhausted() { o.exhausted.Add(1) }
@@ -0,0 +1,62 @@
+package idgenexpvar
+
+import (
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+func TestObserverCounters(t *testing.T) {
+	m := new(expvar.Map).Init()
+	obs := New(m)
+
+	// A clock that only moves when the test says so; each read is signalled
+	// so the test knows Generate has seen the current time.
+	var mu sync.Mutex
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	reads := make(chan struct{}, 1)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case reads <- struct{}{}:
+		default:
+		}
+		return now
+	}
+	g, err := idgen.New(idgen.WithObserver(obs), idgen.WithClock(clock))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	g.Generate()
+	<-reads
+	// The clock has not moved, so the second call waits until it does.
+	done := make(chan struct{})
+	go func() {
+		g.Generate()
+		close(done)
+	}()
+	<-reads
+	mu.Lock()
+	now = now.Add(time.Millisecond)
+	mu.Unlock()
+	<-done
+
+	if got := m.Get("generated").(*expvar.Int).Value(); got != 2 {
+		t.Fatalf("generated = %d, want 2", got)
+	}
+	if got := m.Get("waits").(*expvar.Int).Value(); got != 1 {
+		t.Fatalf("waits = %d, want 1", got)
+	}
+	if got := m.Get("wait_ns").(*expvar.Int).Value(); got < 0 || got > int64(time.Millisecond) {
+		t.Fatalf("wait_ns = %d, want at most 1ms", got)
+	}
+	for _, key := range []string{"clock_regressions", "exhausted"} {
+		if got := m.Get(key).(*expvar.Int).Value(); got != 0 {
+			t.Fatalf("%s = %d, want 0", key, got)
+		}
+	}
+}
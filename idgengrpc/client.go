@@ -0,0 +1,99 @@
+package idgengrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dan-sherwin/idgen"
+	"github.com/dan-sherwin/idgen/idgengrpc/idgenpb"
+	"google.golang.org/grpc"
+)
+
+// Client allocates IDs from a remote Server and implements idgen.IDSource.
+//
+// IDs are decoded locally with codec, a Generator configured exactly like the
+// server's (same epoch, pace, width, bits and obfuscator). Every received ID
+// is parsed, so a server with a different width fails loudly.
+type Client struct {
+	rpc   idgenpb.IDGenClient
+	codec *idgen.Generator
+}
+
+var _ idgen.IDSource = (*Client)(nil)
+
+// NewClient returns a Client using conn for RPCs and codec for decoding.
+func NewClient(conn grpc.ClientConnInterface, codec *idgen.Generator) (*Client, error) {
+	if conn == nil || codec == nil {
+		return nil, errors.New("idgengrpc: conn and codec are required")
+	}
+	return &Client{rpc: idgenpb.NewIDGenClient(conn), codec: codec}, nil
+}
+
+// Next implements idgen.IDSource.
+func (c *Client) Next(ctx context.Context) (idgen.ID, error) {
+	resp, err := c.rpc.Generate(ctx, &idgenpb.GenerateRequest{})
+	if err != nil {
+		return idgen.ID{}, err
+	}
+	return c.parse(resp.GetId())
+}
+
+// NextN implements idgen.IDSource.
+func (c *Client) NextN(ctx context.Context, n int) ([]idgen.ID, error) {
+	if n < 1 {
+		return nil, errors.New("n must be >= 1")
+	}
+	resp, err := c.rpc.GenerateBatch(ctx, &idgenpb.GenerateBatchRequest{Count: uint32(n)})
+	if err != nil {
+		return nil, err
+	}
+	return c.parseAll(resp.GetIds())
+}
+
+// Subscribe streams blocks of blockSize IDs to fn until ctx is cancelled or fn
+// returns an error. Cancellation ends the stream and returns ctx.Err().
+func (c *Client) Subscribe(ctx context.Context, blockSize int, fn func([]idgen.ID) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.rpc.Subscribe(ctx, &idgenpb.SubscribeRequest{BlockSize: uint32(blockSize)})
+	if err != nil {
+		return err
+	}
+	for {
+		block, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		ids, err := c.parseAll(block.GetIds())
+		if err != nil {
+			return err
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) parse(s string) (idgen.ID, error) {
+	id, err := c.codec.ParseID(s)
+	if err != nil {
+		return idgen.ID{}, fmt.Errorf("idgengrpc: server returned undecodable id %q: %w", s, err)
+	}
+	return id, nil
+}
+
+func (c *Client) parseAll(ss []string) ([]idgen.ID, error) {
+	ids := make([]idgen.ID, len(ss))
+	for i, s := range ss {
+		id, err := c.parse(s)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,140 @@
+package idgengrpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/dan-sherwin/idgen"
+	"github.com/dan-sherwin/idgen/idgengrpc/idgenpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient starts a Server on an in-memory listener and returns a
+// connected Client plus the raw stub.
+func newTestClient(t *testing.T, opts ...idgen.Option) (*Client, idgenpb.IDGenClient, *idgen.Generator) {
+	t.Helper()
+	g, err := idgen.New(opts...)
+	if err != nil {
+		t.Fatalf("idgen.New error: %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	idgenpb.RegisterIDGenServer(srv, NewServer(g))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	// The client decodes with its own Generator configured like the server's.
+	codec, _ := idgen.New(opts...)
+	c, err := NewClient(conn, codec)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	return c, idgenpb.NewIDGenClient(conn), g
+}
+
+func TestClientImplementsSource(t *testing.T) {
+	c, _, _ := newTestClient(t)
+	ctx := context.Background()
+	var src idgen.IDSource = c
+
+	first, err := src.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next error: %v", err)
+	}
+	batch, err := src.NextN(ctx, 5)
+	if err != nil {
+		t.Fatalf("NextN error: %v", err)
+	}
+	prev := first
+	for _, id := range batch {
+		if id.Raw() <= prev.Raw() {
+			t.Fatalf("ids not increasing: %v then %v", prev, id)
+		}
+		prev = id
+	}
+	if _, err := src.NextN(ctx, DefaultMaxBatch+1); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("NextN(too many) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	_, stub, g := newTestClient(t)
+	raw := g.Generate()
+	id := g.Format(raw)
+	resp, err := stub.Decode(context.Background(), &idgenpb.DecodeRequest{Id: id})
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if resp.GetId() != id || resp.GetRaw() != raw || !resp.GetTimestamp().AsTime().Equal(g.TimestampFromRaw(raw)) {
+		t.Fatalf("Decode(%q) = %v, want raw %d", id, resp, raw)
+	}
+	if _, err := stub.Decode(context.Background(), &idgenpb.DecodeRequest{Id: "!!!"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Decode(invalid) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestDecodeDerivedID(t *testing.T) {
+	_, stub, g := newTestClient(t, idgen.WithDerivedIDs())
+	id, err := g.FromKey([]byte("partner-a"), []byte("order-1001"))
+	if err != nil {
+		t.Fatalf("FromKey error: %v", err)
+	}
+	resp, err := stub.Decode(context.Background(), &idgenpb.DecodeRequest{Id: id.String()})
+	if err != nil {
+		t.Fatalf("Decode(%q) error: %v", id, err)
+	}
+	if !resp.GetDerived() || resp.GetTimestamp() != nil || resp.GetRaw() != id.Raw() {
+		t.Fatalf("Decode(%q) = %v; want derived with raw %d and no timestamp", id, resp, id.Raw())
+	}
+	plain := g.GenerateID()
+	if resp, err := stub.Decode(context.Background(), &idgenpb.DecodeRequest{Id: plain.String()}); err != nil || resp.GetDerived() {
+		t.Fatalf("Decode(%q) = %v, %v; want a time-based id", plain, resp, err)
+	}
+}
+
+func TestSubscribeStreamsBlocks(t *testing.T) {
+	c, _, _ := newTestClient(t)
+	errDone := errors.New("done")
+	var got []idgen.ID
+	err := c.Subscribe(context.Background(), 4, func(ids []idgen.ID) error {
+		if len(ids) != 4 {
+			t.Fatalf("block of %d ids, want 4", len(ids))
+		}
+		got = append(got, ids...)
+		if len(got) >= 12 {
+			return errDone
+		}
+		return nil
+	})
+	if !errors.Is(err, errDone) {
+		t.Fatalf("Subscribe error = %v, want errDone", err)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Raw() <= got[i-1].Raw() {
+			t.Fatalf("streamed ids not increasing at %d", i)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = c.Subscribe(ctx, 1, func([]idgen.ID) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Subscribe after cancel error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package idgenpb contains the protobuf messages and gRPC stubs for the idgen
+// ID allocation service. Regenerate after editing idgen.proto.
+package idgenpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative idgen.proto
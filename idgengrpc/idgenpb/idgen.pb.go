@@ -0,0 +1,490 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: idgen.proto
+
+// ID allocation service backed by an idgen.Generator.
+
+package idgenpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_idgen_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{0}
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_idgen_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GenerateBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchRequest) Reset() {
+	*x = GenerateBatchRequest{}
+	mi := &file_idgen_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchRequest) ProtoMessage() {}
+
+func (x *GenerateBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchRequest.ProtoReflect.Descriptor instead.
+func (*GenerateBatchRequest) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateBatchRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GenerateBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchResponse) Reset() {
+	*x = GenerateBatchResponse{}
+	mi := &file_idgen_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchResponse) ProtoMessage() {}
+
+func (x *GenerateBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchResponse.ProtoReflect.Descriptor instead.
+func (*GenerateBatchResponse) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateBatchResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type DecodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeRequest) Reset() {
+	*x = DecodeRequest{}
+	mi := &file_idgen_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeRequest) ProtoMessage() {}
+
+func (x *DecodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeRequest.ProtoReflect.Descriptor instead.
+func (*DecodeRequest) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecodeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DecodeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Raw   int64                  `protobuf:"varint,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	// Unset for derived IDs, which carry no timestamp.
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Set for IDs produced by FromKey.
+	Derived       bool `protobuf:"varint,4,opt,name=derived,proto3" json:"derived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeResponse) Reset() {
+	*x = DecodeResponse{}
+	mi := &file_idgen_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeResponse) ProtoMessage() {}
+
+func (x *DecodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeResponse.ProtoReflect.Descriptor instead.
+func (*DecodeResponse) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecodeResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DecodeResponse) GetRaw() int64 {
+	if x != nil {
+		return x.Raw
+	}
+	return 0
+}
+
+func (x *DecodeResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DecodeResponse) GetDerived() bool {
+	if x != nil {
+		return x.Derived
+	}
+	return false
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockSize     uint32                 `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_idgen_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscribeRequest) GetBlockSize() uint32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+type IDBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IDBlock) Reset() {
+	*x = IDBlock{}
+	mi := &file_idgen_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IDBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IDBlock) ProtoMessage() {}
+
+func (x *IDBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IDBlock.ProtoReflect.Descriptor instead.
+func (*IDBlock) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IDBlock) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+var File_idgen_proto protoreflect.FileDescriptor
+
+const file_idgen_proto_rawDesc = "" +
+	"\n" +
+	"\vidgen.proto\x12\bidgen.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x11\n" +
+	"\x0fGenerateRequest\"\"\n" +
+	"\x10GenerateResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x14GenerateBatchRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\")\n" +
+	"\x15GenerateBatchResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\x1f\n" +
+	"\rDecodeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x86\x01\n" +
+	"\x0eDecodeResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03raw\x18\x02 \x01(\x03R\x03raw\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x18\n" +
+	"\aderived\x18\x04 \x01(\bR\aderived\"1\n" +
+	"\x10SubscribeRequest\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x01 \x01(\rR\tblockSize\"\x1b\n" +
+	"\aIDBlock\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids2\x97\x02\n" +
+	"\x05IDGen\x12A\n" +
+	"\bGenerate\x12\x19.idgen.v1.GenerateRequest\x1a\x1a.idgen.v1.GenerateResponse\x12P\n" +
+	"\rGenerateBatch\x12\x1e.idgen.v1.GenerateBatchRequest\x1a\x1f.idgen.v1.GenerateBatchResponse\x12;\n" +
+	"\x06Decode\x12\x17.idgen.v1.DecodeRequest\x1a\x18.idgen.v1.DecodeResponse\x12<\n" +
+	"\tSubscribe\x12\x1a.idgen.v1.SubscribeRequest\x1a\x11.idgen.v1.IDBlock0\x01B0Z.github.com/dan-sherwin/idgen/idgengrpc/idgenpbb\x06proto3"
+
+var (
+	file_idgen_proto_rawDescOnce sync.Once
+	file_idgen_proto_rawDescData []byte
+)
+
+func file_idgen_proto_rawDescGZIP() []byte {
+	file_idgen_proto_rawDescOnce.Do(func() {
+		file_idgen_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_idgen_proto_rawDesc), len(file_idgen_proto_rawDesc)))
+	})
+	return file_idgen_proto_rawDescData
+}
+
+var file_idgen_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_idgen_proto_goTypes = []any{
+	(*GenerateRequest)(nil),       // 0: idgen.v1.GenerateRequest
+	(*GenerateResponse)(nil),      // 1: idgen.v1.GenerateResponse
+	(*GenerateBatchRequest)(nil),  // 2: idgen.v1.GenerateBatchRequest
+	(*GenerateBatchResponse)(nil), // 3: idgen.v1.GenerateBatchResponse
+	(*DecodeRequest)(nil),         // 4: idgen.v1.DecodeRequest
+	(*DecodeResponse)(nil),        // 5: idgen.v1.DecodeResponse
+	(*SubscribeRequest)(nil),      // 6: idgen.v1.SubscribeRequest
+	(*IDBlock)(nil),               // 7: idgen.v1.IDBlock
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_idgen_proto_depIdxs = []int32{
+	8, // 0: idgen.v1.DecodeResponse.timestamp:type_name -> google.protobuf.Timestamp
+	0, // 1: idgen.v1.IDGen.Generate:input_type -> idgen.v1.GenerateRequest
+	2, // 2: idgen.v1.IDGen.GenerateBatch:input_type -> idgen.v1.GenerateBatchRequest
+	4, // 3: idgen.v1.IDGen.Decode:input_type -> idgen.v1.DecodeRequest
+	6, // 4: idgen.v1.IDGen.Subscribe:input_type -> idgen.v1.SubscribeRequest
+	1, // 5: idgen.v1.IDGen.Generate:output_type -> idgen.v1.GenerateResponse
+	3, // 6: idgen.v1.IDGen.GenerateBatch:output_type -> idgen.v1.GenerateBatchResponse
+	5, // 7: idgen.v1.IDGen.Decode:output_type -> idgen.v1.DecodeResponse
+	7, // 8: idgen.v1.IDGen.Subscribe:output_type -> idgen.v1.IDBlock
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_idgen_proto_init() }
+func file_idgen_proto_init() {
+	if File_idgen_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_idgen_proto_rawDesc), len(file_idgen_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_idgen_proto_goTypes,
+		DependencyIndexes: file_idgen_proto_depIdxs,
+		MessageInfos:      file_idgen_proto_msgTypes,
+	}.Build()
+	File_idgen_proto = out.File
+	file_idgen_proto_goTypes = nil
+	file_idgen_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: idgen.proto
+
+// ID allocation service backed by an idgen.Generator.
+
+package idgenpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IDGen_Generate_FullMethodName      = "/idgen.v1.IDGen/Generate"
+	IDGen_GenerateBatch_FullMethodName = "/idgen.v1.IDGen/GenerateBatch"
+	IDGen_Decode_FullMethodName        = "/idgen.v1.IDGen/Decode"
+	IDGen_Subscribe_FullMethodName     = "/idgen.v1.IDGen/Subscribe"
+)
+
+// IDGenClient is the client API for IDGen service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IDGenClient interface {
+	// Generate returns one new ID.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// GenerateBatch returns count new IDs in order.
+	GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (*GenerateBatchResponse, error)
+	// Decode parses an ID into its raw value and timestamp.
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error)
+	// Subscribe streams blocks of block_size new IDs until the client cancels,
+	// letting clients pre-fetch IDs ahead of demand.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IDBlock], error)
+}
+
+type iDGenClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIDGenClient(cc grpc.ClientConnInterface) IDGenClient {
+	return &iDGenClient{cc}
+}
+
+func (c *iDGenClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, IDGen_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDGenClient) GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (*GenerateBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateBatchResponse)
+	err := c.cc.Invoke(ctx, IDGen_GenerateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDGenClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecodeResponse)
+	err := c.cc.Invoke(ctx, IDGen_Decode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDGenClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IDBlock], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IDGen_ServiceDesc.Streams[0], IDGen_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, IDBlock]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDGen_SubscribeClient = grpc.ServerStreamingClient[IDBlock]
+
+// IDGenServer is the server API for IDGen service.
+// All implementations must embed UnimplementedIDGenServer
+// for forward compatibility.
+type IDGenServer interface {
+	// Generate returns one new ID.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// GenerateBatch returns count new IDs in order.
+	GenerateBatch(context.Context, *GenerateBatchRequest) (*GenerateBatchResponse, error)
+	// Decode parses an ID into its raw value and timestamp.
+	Decode(context.Context, *DecodeRequest) (*DecodeResponse, error)
+	// Subscribe streams blocks of block_size new IDs until the client cancels,
+	// letting clients pre-fetch IDs ahead of demand.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[IDBlock]) error
+	mustEmbedUnimplementedIDGenServer()
+}
+
+// UnimplementedIDGenServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIDGenServer struct{}
+
+func (UnimplementedIDGenServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedIDGenServer) GenerateBatch(context.Context, *GenerateBatchRequest) (*GenerateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateBatch not implemented")
+}
+func (UnimplementedIDGenServer) Decode(context.Context, *DecodeRequest) (*DecodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedIDGenServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[IDBlock]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedIDGenServer) mustEmbedUnimplementedIDGenServer() {}
+func (UnimplementedIDGenServer) testEmbeddedByValue()               {}
+
+// UnsafeIDGenServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IDGenServer will
+// result in compilation errors.
+type UnsafeIDGenServer interface {
+	mustEmbedUnimplementedIDGenServer()
+}
+
+func RegisterIDGenServer(s grpc.ServiceRegistrar, srv IDGenServer) {
+	// If the following call pancis, it indicates UnimplementedIDGenServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IDGen_ServiceDesc, srv)
+}
+
+func _IDGen_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDGenServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDGen_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDGenServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDGen_GenerateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDGenServer).GenerateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDGen_GenerateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDGenServer).GenerateBatch(ctx, req.(*GenerateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDGen_Decode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDGenServer).Decode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDGen_Decode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDGenServer).Decode(ctx, req.(*DecodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDGen_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IDGenServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, IDBlock]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDGen_SubscribeServer = grpc.ServerStreamingServer[IDBlock]
+
+// IDGen_ServiceDesc is the grpc.ServiceDesc for IDGen service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IDGen_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "idgen.v1.IDGen",
+	HandlerType: (*IDGenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _IDGen_Generate_Handler,
+		},
+		{
+			MethodName: "GenerateBatch",
+			Handler:    _IDGen_GenerateBatch_Handler,
+		},
+		{
+			MethodName: "Decode",
+			Handler:    _IDGen_Decode_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _IDGen_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "idgen.proto",
+}
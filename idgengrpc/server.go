@@ -0,0 +1,97 @@
+// Package idgengrpc serves an idgen.Generator over gRPC and provides a client
+// that implements idgen.IDSource, so callers can swap a local Generator for a
+// remote allocator. The service is defined in idgenpb/idgen.proto.
+package idgengrpc
+
+import (
+	"context"
+
+	"github.com/dan-sherwin/idgen"
+	"github.com/dan-sherwin/idgen/idgengrpc/idgenpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultMaxBatch bounds GenerateBatch counts and Subscribe block sizes.
+const DefaultMaxBatch = 1000
+
+// Server implements idgenpb.IDGenServer on top of a Generator.
+type Server struct {
+	idgenpb.UnimplementedIDGenServer
+
+	g        *idgen.Generator
+	maxBatch int
+}
+
+// NewServer returns a Server backed by g. Register it with
+// idgenpb.RegisterIDGenServer.
+func NewServer(g *idgen.Generator) *Server {
+	return &Server{g: g, maxBatch: DefaultMaxBatch}
+}
+
+// Generate implements idgenpb.IDGenServer.
+func (s *Server) Generate(ctx context.Context, _ *idgenpb.GenerateRequest) (*idgenpb.GenerateResponse, error) {
+	id, err := s.g.Next(ctx)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return &idgenpb.GenerateResponse{Id: id.String()}, nil
+}
+
+// GenerateBatch implements idgenpb.IDGenServer.
+func (s *Server) GenerateBatch(ctx context.Context, req *idgenpb.GenerateBatchRequest) (*idgenpb.GenerateBatchResponse, error) {
+	ids, err := s.block(ctx, req.GetCount())
+	if err != nil {
+		return nil, err
+	}
+	return &idgenpb.GenerateBatchResponse{Ids: ids}, nil
+}
+
+// Decode implements idgenpb.IDGenServer. IDs produced by FromKey carry no
+// timestamp; they are answered with derived set and timestamp unset.
+func (s *Server) Decode(_ context.Context, req *idgenpb.DecodeRequest) (*idgenpb.DecodeResponse, error) {
+	id, err := s.g.ParseID(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if id.IsDerived() {
+		return &idgenpb.DecodeResponse{Id: id.String(), Raw: id.Raw(), Derived: true}, nil
+	}
+	return &idgenpb.DecodeResponse{
+		Id:        id.String(),
+		Raw:       id.Raw(),
+		Timestamp: timestamppb.New(id.Time()),
+	}, nil
+}
+
+// Subscribe implements idgenpb.IDGenServer. Blocks are produced only as fast
+// as the stream accepts them, so a slow client does not burn ticks.
+func (s *Server) Subscribe(req *idgenpb.SubscribeRequest, stream idgenpb.IDGen_SubscribeServer) error {
+	ctx := stream.Context()
+	for {
+		ids, err := s.block(ctx, req.GetBlockSize())
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&idgenpb.IDBlock{Ids: ids}); err != nil {
+			return err
+		}
+	}
+}
+
+// block generates n formatted IDs, validating n against maxBatch.
+func (s *Server) block(ctx context.Context, n uint32) ([]string, error) {
+	if n < 1 || int(n) > s.maxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "count must be in [1,%d]", s.maxBatch)
+	}
+	ids, err := s.g.NextN(ctx, int(n))
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out, nil
+}
@@ -0,0 +1,190 @@
+// Package idgenhttp serves an idgen.Generator over HTTP so services in other
+// languages can allocate and decode the same IDs.
+//
+// Endpoints (JSON responses):
+//   - POST /ids?n=N   generate N IDs (default 1)
+//   - GET  /ids/{id}  decode an ID to its raw tick, timestamp and layout fields
+//   - GET  /config    the generator's epoch, pace, width, bits, horizon and fingerprint
+//
+// Generation is rate limited to the generator's pace: each request reserves
+// n paces, and requests that would queue longer than the configured maximum
+// are rejected with 429 Too Many Requests and a Retry-After header.
+package idgenhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+// Handler is an http.Handler backed by a Generator.
+type Handler struct {
+	g        *idgen.Generator
+	maxBatch int
+	maxWait  time.Duration
+	mux      *http.ServeMux
+
+	mu   sync.Mutex
+	next time.Time // when the next reserved pace slot starts
+}
+
+// Option configures a Handler.
+type Option func(*Handler) error
+
+// WithMaxBatch limits how many IDs one POST /ids request may ask for (default 1000).
+func WithMaxBatch(n int) Option {
+	return func(h *Handler) error {
+		if n < 1 {
+			return errors.New("max batch must be >= 1")
+		}
+		h.maxBatch = n
+		return nil
+	}
+}
+
+// WithMaxWait limits how long a request may queue behind earlier reservations
+// before it is rejected with 429 (default 1s).
+func WithMaxWait(d time.Duration) Option {
+	return func(h *Handler) error {
+		if d < 0 {
+			return errors.New("max wait must be >= 0")
+		}
+		h.maxWait = d
+		return nil
+	}
+}
+
+// New returns a Handler serving g.
+func New(g *idgen.Generator, opts ...Option) (*Handler, error) {
+	if g == nil {
+		return nil, errors.New("generator cannot be nil")
+	}
+	h := &Handler{g: g, maxBatch: 1000, maxWait: time.Second}
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			return nil, err
+		}
+	}
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("POST /ids", h.generate)
+	h.mux.HandleFunc("GET /ids/{id}", h.decode)
+	h.mux.HandleFunc("GET /config", h.config)
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// GenerateResponse is the body returned by POST /ids.
+type GenerateResponse struct {
+	IDs []string `json:"ids"`
+}
+
+// DecodeResponse is the body returned by GET /ids/{id}.
+type DecodeResponse struct {
+	ID        string    `json:"id"`
+	Raw       int64     `json:"raw"`
+	Timestamp time.Time `json:"timestamp,omitzero"` // absent for derived IDs
+	Derived   bool      `json:"derived,omitempty"`  // produced by FromKey
+	// Fields holds every layout field, such as node, when the generator has a
+	// Layout.
+	Fields idgen.Fields `json:"fields,omitempty"`
+}
+
+// ConfigResponse is the body returned by GET /config.
+type ConfigResponse struct {
+	Epoch   time.Time `json:"epoch"`
+	PaceMS  float64   `json:"pace_ms"`
+	Width   int       `json:"width"`
+	Bits    uint      `json:"bits"`
+	Horizon time.Time `json:"horizon"`
+	// Fingerprint identifies the encoding; clients can compare it with their own.
+	Fingerprint string `json:"fingerprint"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) generate(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if s := r.URL.Query().Get("n"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > h.maxBatch {
+			writeError(w, http.StatusBadRequest, "n must be an integer in [1,"+strconv.Itoa(h.maxBatch)+"]")
+			return
+		}
+		n = v
+	}
+	if retry, ok := h.reserve(n); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+	resp := GenerateResponse{IDs: make([]string, n)}
+	for i := range resp.IDs {
+		if err := r.Context().Err(); err != nil {
+			return // client went away; stop consuming ticks
+		}
+		resp.IDs[i] = h.g.Format(h.g.Generate())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request) {
+	id, err := h.g.ParseID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp := DecodeResponse{ID: id.String(), Raw: id.Raw(), Timestamp: id.Time(), Derived: id.IsDerived()}
+	if l := h.g.Layout(); l != nil && !resp.Derived {
+		resp.Fields = l.Decompose(id.Raw())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) config(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, ConfigResponse{
+		Epoch:       h.g.Epoch(),
+		PaceMS:      float64(h.g.Pace()) / float64(time.Millisecond),
+		Width:       h.g.Width(),
+		Bits:        h.g.Bits(),
+		Horizon:     h.g.Horizon(),
+		Fingerprint: h.g.Fingerprint(),
+	})
+}
+
+// reserve books n pace slots. It fails, returning how long to back off, if the
+// reservation would start more than maxWait from now.
+func (h *Handler) reserve(n int) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	if h.next.Before(now) {
+		h.next = now
+	}
+	if wait := h.next.Sub(now); wait > h.maxWait {
+		return wait - h.maxWait, false
+	}
+	h.next = h.next.Add(time.Duration(n) * h.g.Pace())
+	return 0, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
@@ -0,0 +1,161 @@
+package idgenhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+func newTestServer(t *testing.T, opts ...Option) (*httptest.Server, *idgen.Generator) {
+	t.Helper()
+	g, err := idgen.New()
+	if err != nil {
+		t.Fatalf("idgen.New error: %v", err)
+	}
+	h, err := New(g, opts...)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv, g
+}
+
+func doJSON(t *testing.T, method, url string, want int, v any) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: status %d, want %d", method, url, resp.StatusCode, want)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: decode: %v", method, url, err)
+		}
+	}
+	return resp
+}
+
+func TestGenerateAndDecode(t *testing.T) {
+	srv, g := newTestServer(t)
+
+	var gen GenerateResponse
+	doJSON(t, http.MethodPost, srv.URL+"/ids?n=3", http.StatusOK, &gen)
+	if len(gen.IDs) != 3 {
+		t.Fatalf("got %d ids, want 3", len(gen.IDs))
+	}
+	for _, id := range gen.IDs {
+		var dec DecodeResponse
+		doJSON(t, http.MethodGet, srv.URL+"/ids/"+id, http.StatusOK, &dec)
+		raw, _ := g.Parse(id)
+		if dec.ID != id || dec.Raw != raw || !dec.Timestamp.Equal(g.TimestampFromRaw(raw)) {
+			t.Fatalf("decode %q = %+v, want raw %d", id, dec, raw)
+		}
+	}
+
+	doJSON(t, http.MethodGet, srv.URL+"/ids/!!!", http.StatusBadRequest, nil)
+	doJSON(t, http.MethodPost, srv.URL+"/ids?n=0", http.StatusBadRequest, nil)
+	doJSON(t, http.MethodPost, srv.URL+"/ids?n=1001", http.StatusBadRequest, nil)
+	doJSON(t, http.MethodGet, srv.URL+"/ids", http.StatusMethodNotAllowed, nil)
+}
+
+func TestDecodeDerivedID(t *testing.T) {
+	g, err := idgen.New(idgen.WithDerivedIDs())
+	if err != nil {
+		t.Fatalf("idgen.New error: %v", err)
+	}
+	h, err := New(g)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	id, err := g.FromKey([]byte("partner-a"), []byte("order-1001"))
+	if err != nil {
+		t.Fatalf("FromKey error: %v", err)
+	}
+	var body map[string]any
+	doJSON(t, http.MethodGet, srv.URL+"/ids/"+id.String(), http.StatusOK, &body)
+	if _, ok := body["timestamp"]; ok || body["derived"] != true || body["id"] != id.String() {
+		t.Fatalf("decode %q = %v, want derived and no timestamp", id, body)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	srv, g := newTestServer(t)
+	var cfg ConfigResponse
+	doJSON(t, http.MethodGet, srv.URL+"/config", http.StatusOK, &cfg)
+	if !cfg.Epoch.Equal(g.Epoch()) || cfg.PaceMS != 1 || cfg.Width != g.Width() || cfg.Bits != g.Bits() || !cfg.Horizon.Equal(g.Horizon()) || cfg.Fingerprint != g.Fingerprint() {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestRateLimitFollowsPace(t *testing.T) {
+	g, _ := idgen.New()
+	h, err := New(g, WithMaxWait(0))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	// Book a minute of paces; a request arriving behind them must queue and
+	// is rejected because no queueing is allowed.
+	if _, ok := h.reserve(60000); !ok {
+		t.Fatal("first reservation rejected")
+	}
+	resp := doJSON(t, http.MethodPost, srv.URL+"/ids?n=1", http.StatusTooManyRequests, nil)
+	if s := resp.Header.Get("Retry-After"); s == "" || s == "0" {
+		t.Fatalf("Retry-After = %q, want the remaining booked time", s)
+	}
+	// Allowing enough queueing admits it.
+	h.maxWait = 2 * time.Minute
+	doJSON(t, http.MethodPost, srv.URL+"/ids?n=1", http.StatusOK, nil)
+}
+
+func TestDecodeLayoutFields(t *testing.T) {
+	l, _ := idgen.ParseLayout("tick:38,node:5,seq:3")
+	g, err := idgen.New(idgen.WithWidth(9), idgen.WithLayout(l), idgen.WithFieldValues(idgen.Fields{"node": 9}))
+	if err != nil {
+		t.Fatalf("idgen.New error: %v", err)
+	}
+	h, _ := New(g)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	id := g.GenerateID()
+	var dec DecodeResponse
+	doJSON(t, http.MethodGet, srv.URL+"/ids/"+id.String(), http.StatusOK, &dec)
+	if dec.Fields["node"] != 9 || dec.Fields["seq"] != 0 || !dec.Timestamp.Equal(id.Time()) {
+		t.Fatalf("decode %q = %+v, want node=9 seq=0", id, dec)
+	}
+
+	plain, pg := newTestServer(t)
+	var body map[string]any
+	doJSON(t, http.MethodGet, plain.URL+"/ids/"+pg.Format(pg.Generate()), http.StatusOK, &body)
+	if _, ok := body["fields"]; ok {
+		t.Fatalf("decode without a layout = %v, want no fields", body)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected error for nil generator")
+	}
+	g, _ := idgen.New()
+	if _, err := New(g, WithMaxBatch(0)); err == nil {
+		t.Fatal("expected error for max batch 0")
+	}
+	if _, err := New(g, WithMaxWait(-time.Second)); err == nil {
+		t.Fatal("expected error for negative max wait")
+	}
+}
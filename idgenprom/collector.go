@@ -0,0 +1,90 @@
+// Package idgenprom exposes idgen.Observer callbacks as Prometheus metrics.
+//
+//	c := idgenprom.NewCollector("myapp")
+//	g, _ := idgen.New(idgen.WithObserver(c))
+//	c.SetHorizon(g.Horizon())
+//	prometheus.MustRegister(c)
+package idgenprom
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector implements both idgen.Observer and prometheus.Collector.
+type Collector struct {
+	generated   atomic.Uint64
+	waits       atomic.Uint64
+	waitNanos   atomic.Int64
+	regressions atomic.Uint64
+	exhausted   atomic.Uint64
+	horizon     atomic.Int64 // unix seconds; 0 when unset
+
+	generatedDesc   *prometheus.Desc
+	waitsDesc       *prometheus.Desc
+	waitSecondsDesc *prometheus.Desc
+	regressionsDesc *prometheus.Desc
+	exhaustedDesc   *prometheus.Desc
+	horizonDesc     *prometheus.Desc
+}
+
+// NewCollector returns a Collector whose metric names are prefixed with
+// namespace (if non-empty) and the "idgen" subsystem.
+func NewCollector(namespace string) *Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "idgen", name), help, nil, nil)
+	}
+	return &Collector{
+		generatedDesc:   desc("generated_total", "IDs returned by Generate."),
+		waitsDesc:       desc("waits_total", "Generate calls that slept before issuing a tick."),
+		waitSecondsDesc: desc("wait_seconds_total", "Total time Generate spent sleeping."),
+		regressionsDesc: desc("clock_regressions_total", "Wall clock regressions behind the last issued tick."),
+		exhaustedDesc:   desc("exhausted_total", "Ticks issued past the horizon, whose IDs wrap around."),
+		horizonDesc:     desc("horizon_timestamp_seconds", "Unix time of the last tick that encodes without wrapping."),
+	}
+}
+
+// SetHorizon records the generator's horizon for the horizon gauge.
+func (c *Collector) SetHorizon(t time.Time) { c.horizon.Store(t.Unix()) }
+
+// Generated implements idgen.Observer.
+func (c *Collector) Generated() { c.generated.Add(1) }
+
+// Waited implements idgen.Observer.
+func (c *Collector) Waited(d time.Duration) {
+	c.waits.Add(1)
+	c.waitNanos.Add(int64(d))
+}
+
+// ClockRegression implements idgen.Observer.
+func (c *Collector) ClockRegression(time.Duration) { c.regressions.Add(1) }
+
+// Exhausted implements idgen.Observer.
+func (c *Collector) Exhausted() { c.exhausted.Add(1) }
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.generatedDesc
+	ch <- c.waitsDesc
+	ch <- c.waitSecondsDesc
+	ch <- c.regressionsDesc
+	ch <- c.exhaustedDesc
+	ch <- c.horizonDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	counter(c.generatedDesc, float64(c.generated.Load()))
+	counter(c.waitsDesc, float64(c.waits.Load()))
+	counter(c.waitSecondsDesc, time.Duration(c.waitNanos.Load()).Seconds())
+	counter(c.regressionsDesc, float64(c.regressions.Load()))
+	counter(c.exhaustedDesc, float64(c.exhausted.Load()))
+	if h := c.horizon.Load(); h != 0 {
+		ch <- prometheus.MustNewConstMetric(c.horizonDesc, prometheus.GaugeValue, float64(h))
+	}
+}
@@ -0,0 +1,40 @@
+package idgenprom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dan-sherwin/idgen"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectorWithLocalRegistry(t *testing.T) {
+	c := NewCollector("test")
+	g, err := idgen.New(idgen.WithObserver(c))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	c.SetHorizon(g.Horizon())
+	g.Generate()
+	g.Generate()
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	want := `
+# HELP test_idgen_generated_total IDs returned by Generate.
+# TYPE test_idgen_generated_total counter
+test_idgen_generated_total 2
+# HELP test_idgen_exhausted_total Ticks issued past the horizon, whose IDs wrap around.
+# TYPE test_idgen_exhausted_total counter
+test_idgen_exhausted_total 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "test_idgen_generated_total", "test_idgen_exhausted_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := testutil.GatherAndCount(reg); err != nil || n != 6 {
+		t.Fatalf("GatherAndCount = %d, %v; want 6 metrics including the horizon gauge", n, err)
+	}
+}
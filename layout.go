@@ -0,0 +1,220 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TickField is the name of the mandatory time field in a Layout.
+const TickField = "tick"
+
+// RandomField names the random bits in Decode and Compose for generators
+// created with WithRandomBits.
+const RandomField = "rand"
+
+// CounterField names the logical counter in Decode and Compose for generators
+// created with WithHLC.
+const CounterField = "counter"
+
+// Field is a named bit field in a Layout.
+type Field struct {
+	Name string
+	Bits uint
+}
+
+// Fields holds decoded or to-be-composed field values keyed by field name.
+type Fields map[string]uint64
+
+// Layout describes how a raw value is split into named bit fields, listed from
+// most to least significant. The first field must be TickField so raw values
+// stay ordered by time.
+type Layout struct {
+	fields []Field
+	shifts []uint // right shift to reach each field
+	bits   uint
+}
+
+// NewLayout builds a Layout from fields, most significant first. Names must be
+// unique and non-empty, every field needs at least one bit, the first field
+// must be TickField and the total must not exceed 63 bits.
+func NewLayout(fields ...Field) (*Layout, error) {
+	if len(fields) == 0 || fields[0].Name != TickField {
+		return nil, fmt.Errorf("layout: first field must be %q", TickField)
+	}
+	l := &Layout{fields: append([]Field(nil), fields...), shifts: make([]uint, len(fields))}
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if f.Name == "" || strings.ContainsAny(f.Name, ":, ") {
+			return nil, fmt.Errorf("layout: invalid field name %q", f.Name)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("layout: duplicate field %q", f.Name)
+		}
+		seen[f.Name] = true
+		if f.Bits == 0 {
+			return nil, fmt.Errorf("layout: field %q must have at least 1 bit", f.Name)
+		}
+		l.bits += f.Bits
+	}
+	if l.bits > 63 {
+		return nil, fmt.Errorf("layout: %d bits exceeds 63", l.bits)
+	}
+	shift := l.bits
+	for i, f := range l.fields {
+		shift -= f.Bits
+		l.shifts[i] = shift
+	}
+	return l, nil
+}
+
+// ParseLayout parses a spec such as "tick:33,node:5,seq:3".
+func ParseLayout(spec string) (*Layout, error) {
+	var fields []Field
+	for _, part := range strings.Split(spec, ",") {
+		name, bitsStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("layout: field %q must be name:bits", part)
+		}
+		n, err := strconv.ParseUint(bitsStr, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("layout: field %q: invalid bit count %q", name, bitsStr)
+		}
+		fields = append(fields, Field{Name: name, Bits: uint(n)})
+	}
+	return NewLayout(fields...)
+}
+
+// String returns the layout in ParseLayout syntax.
+func (l *Layout) String() string {
+	parts := make([]string, len(l.fields))
+	for i, f := range l.fields {
+		parts[i] = f.Name + ":" + strconv.FormatUint(uint64(f.Bits), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Bits returns the total number of bits in the layout.
+func (l *Layout) Bits() uint { return l.bits }
+
+// Fields returns the layout's fields, most significant first.
+func (l *Layout) Fields() []Field { return append([]Field(nil), l.fields...) }
+
+// Compose packs values into a raw value. Missing fields are zero; unknown
+// names and values that do not fit their field are errors.
+func (l *Layout) Compose(values Fields) (int64, error) {
+	var raw uint64
+	known := 0
+	for i, f := range l.fields {
+		v, ok := values[f.Name]
+		if !ok {
+			continue
+		}
+		known++
+		if v >= uint64(1)<<f.Bits {
+			return 0, fmt.Errorf("layout: value %d overflows %d-bit field %q", v, f.Bits, f.Name)
+		}
+		raw |= v << l.shifts[i]
+	}
+	if known != len(values) {
+		for name := range values {
+			if !l.has(name) {
+				return 0, fmt.Errorf("layout: unknown field %q", name)
+			}
+		}
+	}
+	return int64(raw), nil
+}
+
+// Decompose splits a raw value into its fields.
+func (l *Layout) Decompose(raw int64) Fields {
+	out := make(Fields, len(l.fields))
+	for i, f := range l.fields {
+		out[f.Name] = uint64(raw) >> l.shifts[i] & (uint64(1)<<f.Bits - 1)
+	}
+	return out
+}
+
+func (l *Layout) has(name string) bool {
+	for _, f := range l.fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Layout returns the layout set by WithLayout, or nil.
+func (g *Generator) Layout() *Layout { return g.layout }
+
+// Decode parses a formatted ID and returns all of its layout fields. Without a
+// layout the result holds TickField, plus RandomField if WithRandomBits is set.
+// IDs produced by FromKey have no fields and are rejected with ErrDerivedID.
+func (g *Generator) Decode(id string) (Fields, error) {
+	raw, err := g.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	if g.IsDerived(raw) {
+		return nil, ErrDerivedID
+	}
+	if g.layout != nil {
+		return g.layout.Decompose(raw), nil
+	}
+	tick, random := g.Split(raw)
+	f := Fields{TickField: uint64(tick)}
+	if g.randBits > 0 {
+		f[RandomField] = random
+	}
+	if g.hlc {
+		f[CounterField] = uint64(raw) >> g.randBits & (uint64(1)<<g.hlcBits - 1)
+	}
+	return f, nil
+}
+
+// Compose packs field values into a raw value suitable for Format. Without a
+// layout only TickField and, with random bits or WithHLC, RandomField and
+// CounterField are accepted.
+func (g *Generator) Compose(values Fields) (int64, error) {
+	if g.layout != nil {
+		return g.layout.Compose(values)
+	}
+	for name, v := range values {
+		switch {
+		case name == TickField && v >= uint64(1)<<(g.bits-g.tickShift):
+			return 0, fmt.Errorf("layout: value %d overflows %d-bit field %q", v, g.bits-g.tickShift, name)
+		case name == RandomField && g.randBits > 0 && v >= uint64(1)<<g.randBits:
+			return 0, fmt.Errorf("layout: value %d overflows %d-bit field %q", v, g.randBits, name)
+		case name == CounterField && g.hlc && v >= uint64(1)<<g.hlcBits:
+			return 0, fmt.Errorf("layout: value %d overflows %d-bit field %q", v, g.hlcBits, name)
+		case name != TickField && (name != RandomField || g.randBits == 0) && (name != CounterField || !g.hlc):
+			return 0, fmt.Errorf("layout: unknown field %q", name)
+		}
+	}
+	return int64(values[TickField]<<g.tickShift | values[CounterField]<<g.randBits | values[RandomField]), nil
+}
+
+// setupLayout validates static field values against the layout and
+// precomputes the shift and bits Generate uses. Called by New once bits are known.
+func (g *Generator) setupLayout() error {
+	if g.layout == nil {
+		if len(g.fieldValues) > 0 {
+			return errors.New("field values require a layout")
+		}
+		return nil
+	}
+	if g.bits != g.layout.bits {
+		return fmt.Errorf("layout has %d bits but generator uses %d", g.layout.bits, g.bits)
+	}
+	if _, ok := g.fieldValues[TickField]; ok {
+		return fmt.Errorf("field %q is set by Generate", TickField)
+	}
+	static, err := g.layout.Compose(g.fieldValues)
+	if err != nil {
+		return err
+	}
+	g.staticBits = static
+	g.tickShift = g.layout.shifts[0]
+	return nil
+}
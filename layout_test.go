@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestParseLayout(t *testing.T) {
+	l, err := ParseLayout("tick:33, node:5, seq:3")
+	if err != nil {
+		t.Fatalf("ParseLayout error: %v", err)
+	}
+	if l.Bits() != 41 || l.String() != "tick:33,node:5,seq:3" {
+		t.Fatalf("layout = %s (%d bits), want tick:33,node:5,seq:3 (41 bits)", l, l.Bits())
+	}
+	for _, spec := range []string{"", "node:5,tick:33", "tick:33,tick:2", "tick:0", "tick:60,node:4", "tick", "tick:x"} {
+		if _, err := ParseLayout(spec); err == nil {
+			t.Errorf("ParseLayout(%q) should fail", spec)
+		}
+	}
+}
+
+func TestLayoutComposeDecompose(t *testing.T) {
+	l, _ := ParseLayout("tick:33,node:5,seq:3")
+	in := Fields{"tick": 123456789, "node": 17, "seq": 5}
+	raw, err := l.Compose(in)
+	if err != nil {
+		t.Fatalf("Compose error: %v", err)
+	}
+	if want := int64(123456789<<8 | 17<<3 | 5); raw != want {
+		t.Fatalf("Compose = %d, want %d", raw, want)
+	}
+	out := l.Decompose(raw)
+	for k, v := range in {
+		if out[k] != v {
+			t.Fatalf("Decompose[%s] = %d, want %d", k, out[k], v)
+		}
+	}
+	if _, err := l.Compose(Fields{"node": 32}); err == nil {
+		t.Fatal("expected overflow error")
+	}
+	if _, err := l.Compose(Fields{"shard": 1}); err == nil {
+		t.Fatal("expected unknown field error")
+	}
+}
+
+func TestGeneratorLayout(t *testing.T) {
+	l, _ := ParseLayout("tick:38,node:5,seq:3")
+	g, err := New(WithWidth(9), WithLayout(l), WithFieldValues(Fields{"node": 9}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if g.Bits() != 46 {
+		t.Fatalf("Bits() = %d, want 46", g.Bits())
+	}
+	before := time.Now().UTC().Add(-time.Millisecond)
+	id := g.GenerateID()
+	f, err := g.Decode(id.String())
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if f["node"] != 9 || f["seq"] != 0 {
+		t.Fatalf("Decode = %v, want node=9 seq=0", f)
+	}
+	if ts := id.Time(); ts.Before(before) || ts.After(time.Now().UTC()) {
+		t.Fatalf("Time() = %v, want about now", ts)
+	}
+	raw, err := g.Compose(f)
+	if err != nil || raw != id.Raw() {
+		t.Fatalf("Compose(%v) = %d, %v; want %d", f, raw, err, id.Raw())
+	}
+	if want := g.Epoch().Add(time.Duration(1<<38-1) * time.Millisecond); !g.Horizon().Equal(want) {
+		t.Fatalf("Horizon() = %v, want %v", g.Horizon(), want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("created", "order", id)
+	var rec struct {
+		Order struct {
+			Node uint64 `json:"node"`
+		} `json:"order"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil || rec.Order.Node != 9 {
+		t.Fatalf("logged %q, want node=9", buf.String())
+	}
+
+	plain, _ := New()
+	if f, err := plain.Decode(plain.Format(42)); err != nil || len(f) != 1 || f["tick"] != 42 {
+		t.Fatalf("Decode without layout = %v, %v; want tick=42", f, err)
+	}
+}
+
+func TestGeneratorLayoutValidation(t *testing.T) {
+	l, _ := ParseLayout("tick:33,node:5,seq:3")
+	cases := map[string][]Option{
+		"bits mismatch":   {WithLayout(l), WithBits(40)},
+		"node overflow":   {WithLayout(l), WithFieldValues(Fields{"node": 32})},
+		"tick value":      {WithLayout(l), WithFieldValues(Fields{"tick": 1})},
+		"no layout":       {WithFieldValues(Fields{"node": 1})},
+		"width too small": {WithLayout(l), WithWidth(7)},
+	}
+	for name, opts := range cases {
+		if _, err := New(opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := NewSharded(2, WithLayout(l)); err == nil {
+		t.Error("expected error combining layout with sharding")
+	}
+}
+
+func TestConfigLayout(t *testing.T) {
+	c := Config{Layout: "tick:33,node:5,seq:3", Epoch: time.Now()}
+	g, err := NewFromConfig(c)
+	if err != nil {
+		t.Fatalf("NewFromConfig error: %v", err)
+	}
+	got, err := g.Config()
+	if err != nil || got.Layout != c.Layout {
+		t.Fatalf("Config() = %+v, %v; want layout %q", got, err, c.Layout)
+	}
+	plain, _ := New(WithBits(41))
+	if g.Fingerprint() == plain.Fingerprint() {
+		t.Fatal("layout should change the fingerprint")
+	}
+	if err := (Config{Layout: "tick:33,node:5", Bits: 41}).Validate(); err == nil {
+		t.Fatal("expected bits/layout mismatch error")
+	}
+}
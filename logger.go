@@ -0,0 +1,83 @@
+package idgen
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Thresholds for WithLogger.
+const (
+	longWaitPaces  = 10                   // waits longer than this many paces are logged
+	horizonWarning = 365 * 24 * time.Hour // warn at construction if the horizon is closer
+)
+
+// loggingObserver reports anomalies through a slog.Logger.
+type loggingObserver struct {
+	l         *slog.Logger
+	longWait  time.Duration
+	exhausted atomic.Bool // log exhaustion once, not on every tick
+}
+
+func (o *loggingObserver) Generated() {}
+
+func (o *loggingObserver) Waited(d time.Duration) {
+	if d > o.longWait {
+		o.l.Warn("idgen: long wait in Generate", slog.Duration("waited", d))
+	}
+}
+
+func (o *loggingObserver) ClockRegression(d time.Duration) {
+	o.l.Warn("idgen: wall clock moved backwards; waiting to catch up", slog.Duration("behind", d))
+}
+
+func (o *loggingObserver) Exhausted() {
+	if o.exhausted.CompareAndSwap(false, true) {
+		o.l.Error("idgen: horizon exceeded; IDs now wrap and collide with earlier ones")
+	}
+}
+
+// observers fans callbacks out to several Observers.
+type observers []Observer
+
+func (os observers) Generated() {
+	for _, o := range os {
+		o.Generated()
+	}
+}
+
+func (os observers) Waited(d time.Duration) {
+	for _, o := range os {
+		o.Waited(d)
+	}
+}
+
+func (os observers) ClockRegression(d time.Duration) {
+	for _, o := range os {
+		o.ClockRegression(d)
+	}
+}
+
+func (os observers) Exhausted() {
+	for _, o := range os {
+		o.Exhausted()
+	}
+}
+
+// attachLogger wires the logger into the observer chain and warns if the
+// horizon is near. It is called by New once the configuration is final.
+func (g *Generator) attachLogger() {
+	if g.logger == nil {
+		return
+	}
+	lo := &loggingObserver{l: g.logger, longWait: longWaitPaces * g.pace}
+	if g.obs == nil {
+		g.obs = lo
+	} else {
+		g.obs = observers{g.obs, lo}
+	}
+	if h := g.Horizon(); h.Sub(g.now()) < horizonWarning {
+		g.logger.LogAttrs(context.Background(), slog.LevelWarn, "idgen: horizon is near", slog.Time("horizon", h))
+	}
+}
@@ -0,0 +1,64 @@
+package idgen
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLoggerReportsAnomalies(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	obs := &recordingObserver{}
+
+	// 2 bits at 1ms: the horizon is 3ms after the epoch, so construction
+	// warns and every tick read after the first few is exhausted.
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var reads atomic.Int64
+	clock := func() time.Time {
+		return epoch.Add(time.Duration(reads.Add(1)) * time.Millisecond)
+	}
+	g, err := New(WithWidth(1), WithBits(2), WithObserver(obs), WithLogger(l), WithClock(clock))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "horizon is near") {
+		t.Fatalf("missing near-horizon warning in %q", buf.String())
+	}
+
+	ahead := (clock().UnixMilli() - g.epochMS) + 5
+	g.lastTick.Store(ahead)
+	g.Generate()
+	g.Generate()
+
+	out := buf.String()
+	if !strings.Contains(out, "wall clock moved backwards") {
+		t.Fatalf("missing clock regression log in %q", out)
+	}
+	if n := strings.Count(out, "horizon exceeded"); n != 1 {
+		t.Fatalf("horizon exceeded logged %d times, want 1: %q", n, out)
+	}
+	// The user observer still sees every callback alongside the logger.
+	if obs.generated != 2 || obs.regressions != 1 || obs.exhausted != 2 {
+		t.Fatalf("observer counts generated=%d regressions=%d exhausted=%d", obs.generated, obs.regressions, obs.exhausted)
+	}
+}
+
+func TestWithLoggerQuietByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	g, err := New(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	g.Generate()
+	g.Generate()
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected log output: %q", buf.String())
+	}
+	if _, err := New(WithLogger(nil)); err == nil {
+		t.Fatal("expected error for nil logger")
+	}
+}
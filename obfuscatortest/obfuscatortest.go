@@ -0,0 +1,203 @@
+// Package obfuscatortest provides quality checks for idgen.Obfuscator
+// implementations.
+//
+// The checks report metrics rather than pass/fail verdicts so different
+// permutations (or round counts) can be compared side by side:
+//   - Bijection: exhaustive permutation and round-trip check on small domains.
+//   - Avalanche: how many output bits flip when a single input bit flips.
+//   - PrefixCollision: how often consecutive inputs share leading base36 characters.
+//   - ChiSquared: uniformity of outputs for a run of consecutive inputs.
+//
+// All sampling is seeded deterministically so results are reproducible.
+package obfuscatortest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+// MaxBijectionBits is the largest domain Bijection will enumerate.
+const MaxBijectionBits = 24
+
+// seed is used for all sampled checks so repeated runs are comparable.
+const seed = 0x1d6e4
+
+// Bijection exhaustively verifies that ob permutes [0, 2^k) and that
+// Deobfuscate inverts Obfuscate. It returns the first violation found.
+func Bijection(ob idgen.Obfuscator) error {
+	k := ob.DomainBits()
+	if k > MaxBijectionBits {
+		return fmt.Errorf("bijection: domain of %d bits exceeds %d", k, MaxBijectionBits)
+	}
+	mask := (uint64(1) << k) - 1
+	seen := make([]bool, mask+1)
+	for x := uint64(0); x <= mask; x++ {
+		y := ob.Obfuscate(x)
+		if y > mask {
+			return fmt.Errorf("bijection: Obfuscate(%d)=%d out of range", x, y)
+		}
+		if seen[y] {
+			return fmt.Errorf("bijection: collision at Obfuscate(%d)=%d", x, y)
+		}
+		seen[y] = true
+		if x2 := ob.Deobfuscate(y); x2 != x {
+			return fmt.Errorf("bijection: Deobfuscate(%d)=%d want %d", y, x2, x)
+		}
+	}
+	return nil
+}
+
+// AvalancheResult summarizes single-bit input flips.
+type AvalancheResult struct {
+	Bits    uint // domain bits
+	Samples int  // random inputs per input bit
+	// MeanFlips is the average number of output bits flipped per input bit flip.
+	// An ideal permutation flips Bits/2.
+	MeanFlips float64
+	// Matrix[i][j] is the probability that output bit j flips when input bit i flips.
+	Matrix [][]float64
+	// MaxBias is the largest |Matrix[i][j] - 0.5| observed.
+	MaxBias float64
+}
+
+// Avalanche flips each input bit of samples random inputs and measures which
+// output bits change.
+func Avalanche(ob idgen.Obfuscator, samples int) AvalancheResult {
+	k := ob.DomainBits()
+	mask := (uint64(1) << k) - 1
+	rng := rand.New(rand.NewSource(seed))
+	res := AvalancheResult{Bits: k, Samples: samples, Matrix: make([][]float64, k)}
+	counts := make([][]int, k)
+	for i := range counts {
+		counts[i] = make([]int, k)
+	}
+	total := 0
+	for s := 0; s < samples; s++ {
+		x := rng.Uint64() & mask
+		y := ob.Obfuscate(x)
+		for i := uint(0); i < k; i++ {
+			diff := y ^ ob.Obfuscate(x^(uint64(1)<<i))
+			for j := uint(0); j < k; j++ {
+				if diff>>j&1 == 1 {
+					counts[i][j]++
+					total++
+				}
+			}
+		}
+	}
+	if samples == 0 {
+		return res
+	}
+	for i := range counts {
+		res.Matrix[i] = make([]float64, k)
+		for j, c := range counts[i] {
+			p := float64(c) / float64(samples)
+			res.Matrix[i][j] = p
+			res.MaxBias = math.Max(res.MaxBias, math.Abs(p-0.5))
+		}
+	}
+	res.MeanFlips = float64(total) / float64(samples) / float64(k)
+	return res
+}
+
+// PrefixResult summarizes how often consecutive IDs share a leading prefix.
+type PrefixResult struct {
+	Width     int
+	PrefixLen int
+	Samples   int
+	// Rate is the observed fraction of consecutive pairs sharing PrefixLen leading characters.
+	Rate float64
+	// Expected is the rate for uniformly random outputs over the same domain.
+	Expected float64
+}
+
+// PrefixCollision formats Obfuscate(start+i) as fixed-width base36 for
+// samples+1 consecutive inputs and measures how often neighbours share their
+// first prefixLen characters.
+func PrefixCollision(ob idgen.Obfuscator, width, prefixLen int, start uint64, samples int) PrefixResult {
+	k := ob.DomainBits()
+	mask := (uint64(1) << k) - 1
+	res := PrefixResult{Width: width, PrefixLen: prefixLen, Samples: samples}
+	if samples <= 0 || prefixLen < 1 || prefixLen > width {
+		return res
+	}
+	prefix := func(x uint64) string {
+		s := strconv.FormatUint(ob.Obfuscate(x&mask), 36)
+		for len(s) < width {
+			s = "0" + s
+		}
+		return s[:prefixLen]
+	}
+	hits := 0
+	prev := prefix(start)
+	for i := 1; i <= samples; i++ {
+		cur := prefix(start + uint64(i))
+		if cur == prev {
+			hits++
+		}
+		prev = cur
+	}
+	res.Rate = float64(hits) / float64(samples)
+
+	// Uniform baseline: sum of squared bucket probabilities, where buckets are
+	// the prefixes and the last bucket may be partially filled.
+	d := math.Pow(36, float64(width-prefixLen))
+	n := float64(mask) + 1
+	full := math.Floor(n / d)
+	rem := n - full*d
+	res.Expected = (full*d*d + rem*rem) / (n * n)
+	return res
+}
+
+// ChiSquaredResult reports the chi-squared goodness of fit of outputs
+// against a uniform distribution over equal-width buckets.
+type ChiSquaredResult struct {
+	Buckets   int
+	Samples   int
+	Statistic float64
+	DF        int // degrees of freedom (Buckets-1)
+	// Z is the normal approximation (Statistic-DF)/sqrt(2*DF); |Z| > 3 is suspicious.
+	Z float64
+}
+
+// ChiSquared buckets Obfuscate(start+i) for samples consecutive inputs by
+// their top bits. buckets must be a power of two no larger than 2^k.
+func ChiSquared(ob idgen.Obfuscator, buckets int, start uint64, samples int) (ChiSquaredResult, error) {
+	k := ob.DomainBits()
+	if buckets < 2 || buckets&(buckets-1) != 0 {
+		return ChiSquaredResult{}, fmt.Errorf("chi-squared: buckets must be a power of two >= 2: %d", buckets)
+	}
+	bb := uint(0)
+	for 1<<bb < buckets {
+		bb++
+	}
+	if bb > k {
+		return ChiSquaredResult{}, fmt.Errorf("chi-squared: %d buckets exceed domain of %d bits", buckets, k)
+	}
+	if samples < buckets {
+		return ChiSquaredResult{}, fmt.Errorf("chi-squared: need at least %d samples", buckets)
+	}
+	mask := (uint64(1) << k) - 1
+	obs := make([]int, buckets)
+	for i := 0; i < samples; i++ {
+		obs[ob.Obfuscate((start+uint64(i))&mask)>>(k-bb)]++
+	}
+	exp := float64(samples) / float64(buckets)
+	var stat float64
+	for _, o := range obs {
+		d := float64(o) - exp
+		stat += d * d / exp
+	}
+	df := buckets - 1
+	return ChiSquaredResult{
+		Buckets:   buckets,
+		Samples:   samples,
+		Statistic: stat,
+		DF:        df,
+		Z:         (stat - float64(df)) / math.Sqrt(2*float64(df)),
+	}, nil
+}
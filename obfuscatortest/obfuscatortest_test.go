@@ -0,0 +1,78 @@
+package obfuscatortest
+
+import (
+	"testing"
+
+	"github.com/dan-sherwin/idgen"
+)
+
+// brokenObfuscator maps everything to zero; Bijection must reject it.
+type brokenObfuscator struct{}
+
+func (brokenObfuscator) DomainBits() uint            { return 8 }
+func (brokenObfuscator) Obfuscate(uint64) uint64     { return 0 }
+func (brokenObfuscator) Deobfuscate(y uint64) uint64 { return y }
+
+func TestBijection(t *testing.T) {
+	ob, err := idgen.NewFeistel(16, 4)
+	if err != nil {
+		t.Fatalf("NewFeistel error: %v", err)
+	}
+	if err := Bijection(ob); err != nil {
+		t.Fatalf("Bijection(feistel) error: %v", err)
+	}
+	if err := Bijection(brokenObfuscator{}); err == nil {
+		t.Fatal("expected Bijection to reject a non-permutation")
+	}
+	big, _ := idgen.NewFeistel(41, 4)
+	if err := Bijection(big); err == nil {
+		t.Fatal("expected error for domain too large to enumerate")
+	}
+}
+
+func TestDefaultFeistelQuality(t *testing.T) {
+	ob, err := idgen.NewFeistel(41, 4)
+	if err != nil {
+		t.Fatalf("NewFeistel error: %v", err)
+	}
+	av := Avalanche(ob, 200)
+	if av.MeanFlips < 0.4*float64(av.Bits) || av.MeanFlips > 0.6*float64(av.Bits) {
+		t.Fatalf("mean flips %.2f not near %d/2", av.MeanFlips, av.Bits)
+	}
+	pc := PrefixCollision(ob, 8, 2, 1<<35, 20000)
+	if pc.Rate > 3*pc.Expected {
+		t.Fatalf("prefix collision rate %.5f far above uniform %.5f", pc.Rate, pc.Expected)
+	}
+	cs, err := ChiSquared(ob, 256, 1<<35, 100000)
+	if err != nil {
+		t.Fatalf("ChiSquared error: %v", err)
+	}
+	if cs.Z > 4 || cs.Z < -4 {
+		t.Fatalf("chi-squared z=%.2f (stat=%.1f df=%d)", cs.Z, cs.Statistic, cs.DF)
+	}
+	t.Logf("feistel(41,4): flips=%.2f maxbias=%.3f prefix=%.5f (uniform %.5f) chi2 z=%.2f",
+		av.MeanFlips, av.MaxBias, pc.Rate, pc.Expected, cs.Z)
+}
+
+func TestIdentityFailsQuality(t *testing.T) {
+	ob, err := idgen.NewXorMask(41, 0)
+	if err != nil {
+		t.Fatalf("NewXorMask error: %v", err)
+	}
+	if av := Avalanche(ob, 50); av.MeanFlips != 1 {
+		t.Fatalf("identity mean flips = %.2f, want 1", av.MeanFlips)
+	}
+	if pc := PrefixCollision(ob, 8, 2, 1<<35, 1000); pc.Rate < 0.9 {
+		t.Fatalf("identity prefix collision rate = %.3f, want ~1", pc.Rate)
+	}
+	cs, err := ChiSquared(ob, 256, 1<<35, 10000)
+	if err != nil {
+		t.Fatalf("ChiSquared error: %v", err)
+	}
+	if cs.Z < 100 {
+		t.Fatalf("identity chi-squared z=%.2f, expected a strong rejection", cs.Z)
+	}
+	if _, err := ChiSquared(ob, 3, 0, 100); err == nil {
+		t.Fatal("expected error for non power of two buckets")
+	}
+}
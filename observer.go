@@ -0,0 +1,20 @@
+package idgen
+
+import "time"
+
+// Observer receives instrumentation callbacks from Generate. Implementations
+// must be safe for concurrent use and should return quickly, since they are
+// called on the generation path. See the idgenexpvar and idgenprom packages
+// for ready-made adapters.
+type Observer interface {
+	// Generated is called once for every tick returned by Generate.
+	Generated()
+	// Waited reports how long a Generate call slept before its tick was issued.
+	Waited(d time.Duration)
+	// ClockRegression reports that the wall clock moved behind the last issued
+	// tick by d; Generate waits until the clock catches up.
+	ClockRegression(d time.Duration)
+	// Exhausted is called for each tick issued past the generator's horizon,
+	// where IDs wrap around and collide with earlier ones.
+	Exhausted()
+}
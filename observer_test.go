@@ -0,0 +1,144 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver counts observer callbacks for tests.
+type recordingObserver struct {
+	mu          sync.Mutex
+	generated   int
+	waits       int
+	waited      time.Duration
+	regressions int
+	exhausted   int
+}
+
+func (o *recordingObserver) Generated() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.generated++
+}
+
+func (o *recordingObserver) Waited(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.waits++
+	o.waited += d
+}
+
+func (o *recordingObserver) ClockRegression(time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.regressions++
+}
+
+func (o *recordingObserver) Exhausted() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.exhausted++
+}
+
+// manualClock is a clock that only moves when advanced. Each read is signalled
+// on reads (without blocking), so a test can tell that Generate has seen the
+// current time before moving it on.
+type manualClock struct {
+	mu    sync.Mutex
+	t     time.Time
+	reads chan struct{}
+}
+
+func newManualClock(t time.Time) *manualClock {
+	return &manualClock{t: t, reads: make(chan struct{}, 1)}
+}
+
+func (c *manualClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case c.reads <- struct{}{}:
+	default:
+	}
+	return c.t
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestObserverGeneratedAndWaited(t *testing.T) {
+	obs := &recordingObserver{}
+	clock := newManualClock(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	g, err := New(WithObserver(obs), WithClock(clock.now))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// The current tick is already issued, so Generate has to wait for the
+	// clock to move.
+	g.lastTick.Store(clock.now().UnixMilli() - g.epochMS)
+	<-clock.reads
+	done := make(chan struct{})
+	go func() {
+		g.Generate()
+		close(done)
+	}()
+	<-clock.reads // Generate has read the issued tick
+	clock.advance(time.Millisecond)
+	<-done
+
+	if obs.generated != 1 {
+		t.Fatalf("generated = %d, want 1", obs.generated)
+	}
+	if obs.waits != 1 || obs.waited > time.Millisecond {
+		t.Fatalf("waits = %d (total %v), want 1 of at most 1ms", obs.waits, obs.waited)
+	}
+	if obs.regressions != 0 || obs.exhausted != 0 {
+		t.Fatalf("unexpected regressions=%d exhausted=%d", obs.regressions, obs.exhausted)
+	}
+}
+
+func TestObserverClockRegression(t *testing.T) {
+	obs := &recordingObserver{}
+	g, err := New(WithObserver(obs))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// Pretend a tick slightly in the future was already issued.
+	ahead := (time.Now().UnixMilli() - g.epochMS) + 5
+	g.lastTick.Store(ahead)
+	if got := g.Generate(); got <= ahead {
+		t.Fatalf("Generate() = %d, want > %d", got, ahead)
+	}
+	if obs.regressions != 1 {
+		t.Fatalf("regressions = %d, want 1", obs.regressions)
+	}
+}
+
+func TestObserverExhaustedAndHorizon(t *testing.T) {
+	obs := &recordingObserver{}
+	// 2 bits at 1ms covers only 4ms after the epoch.
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newManualClock(epoch)
+	g, err := New(WithWidth(1), WithBits(2), WithObserver(obs), WithClock(clock.now))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	want := epoch.Add(3 * time.Millisecond)
+	if h := g.Horizon(); !h.Equal(want) {
+		t.Fatalf("Horizon() = %v, want %v", h, want)
+	}
+	clock.advance(time.Second)
+	g.Generate()
+	if obs.exhausted != 1 {
+		t.Fatalf("exhausted = %d, want 1", obs.exhausted)
+	}
+
+	def, _ := New()
+	if h := def.Horizon(); h.Year() < 2090 {
+		t.Fatalf("default Horizon() = %v, want decades ahead", h)
+	}
+}
@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// op records one call: start and end are positions on a shared logical clock.
+type op struct {
+	start, end int64
+	raw        int64
+}
+
+// runOps calls gen from several goroutines and records each call's interval.
+func runOps(workers, perWorker int, gen func() int64) []op {
+	var clock atomic.Int64
+	ops := make([]op, workers*perWorker)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				start := clock.Add(1)
+				raw := gen()
+				ops[w*perWorker+i] = op{start: start, end: clock.Add(1), raw: raw}
+			}
+		}(w)
+	}
+	wg.Wait()
+	return ops
+}
+
+// checkLinearizable fails if some call returned a value not greater than one
+// returned by a call that completed before it started.
+func checkLinearizable(t *testing.T, ops []op) {
+	t.Helper()
+	byEnd := append([]op(nil), ops...)
+	sort.Slice(byEnd, func(i, j int) bool { return byEnd[i].end < byEnd[j].end })
+	byStart := append([]op(nil), ops...)
+	sort.Slice(byStart, func(i, j int) bool { return byStart[i].start < byStart[j].start })
+	maxDone, j := int64(-1), 0
+	for _, b := range byStart {
+		for ; j < len(byEnd) && byEnd[j].end < b.start; j++ {
+			maxDone = max(maxDone, byEnd[j].raw)
+		}
+		if b.raw <= maxDone {
+			t.Fatalf("call starting at %d returned %d after %d had already been returned", b.start, b.raw, maxDone)
+		}
+	}
+}
+
+func workers() int { return max(8, runtime.GOMAXPROCS(0)) }
+
+func TestGenerateLinearizable(t *testing.T) {
+	g, err := New(WithPace(1))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	checkLinearizable(t, runOps(workers(), 20, g.Generate))
+}
+
+func TestShardedStrictOrdering(t *testing.T) {
+	s, err := NewSharded(4, WithStrictOrdering())
+	if err != nil {
+		t.Fatalf("NewSharded error: %v", err)
+	}
+	checkLinearizable(t, runOps(workers(), 10, s.Generate))
+}
+
+func TestPooledStrictOrdering(t *testing.T) {
+	g, err := New(WithStrictOrdering())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	src, err := NewPooledSource(g, 10)
+	if err != nil {
+		t.Fatalf("NewPooledSource error: %v", err)
+	}
+	ctx := context.Background()
+	first, _ := src.Next(ctx) // fills the pool
+	direct := g.Generate()    // bypasses the pool
+	next, err := src.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next error: %v", err)
+	}
+	if next.Raw() <= direct || next.Raw() <= first.Raw() {
+		t.Fatalf("pooled %d after direct %d (first %d), want greater", next.Raw(), direct, first.Raw())
+	}
+}
+
+func TestNextNStrictOrderingContiguous(t *testing.T) {
+	g, err := New(WithStrictOrdering())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	const batches, size = 4, 5
+	out := make([][]ID, batches)
+	var wg sync.WaitGroup
+	wg.Add(batches + 1)
+	for b := range out {
+		go func(b int) {
+			defer wg.Done()
+			out[b], _ = g.NextN(context.Background(), size)
+		}(b)
+	}
+	var singles []int64
+	go func() {
+		defer wg.Done()
+		for i := 0; i < size; i++ {
+			singles = append(singles, g.Generate())
+		}
+	}()
+	wg.Wait()
+	for _, ids := range out {
+		lo, hi := ids[0].Raw(), ids[size-1].Raw()
+		for _, other := range out {
+			if o := other[0].Raw(); o != lo && o > lo && o < hi {
+				t.Fatalf("batch [%d, %d] interleaved with batch starting at %d", lo, hi, o)
+			}
+		}
+		for _, v := range singles {
+			if v > lo && v < hi {
+				t.Fatalf("batch [%d, %d] interleaved with single %d", lo, hi, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package idgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// affine implements x -> (a*x + b) mod 2^k with an odd multiplier a.
+type affine struct {
+	k    uint
+	a    uint64
+	aInv uint64
+	b    uint64
+	mask uint64
+}
+
+// NewAffine creates a k-bit affine permutation x -> (a*x + b) mod 2^k.
+// k must be in [1, 63], a must be odd, and b must fit in k bits.
+func NewAffine(k uint, a, b uint64) (Obfuscator, error) {
+	if k == 0 || k > 63 {
+		return nil, fmt.Errorf("affine: k out of range: %d", k)
+	}
+	if a%2 == 0 {
+		return nil, fmt.Errorf("affine: multiplier must be odd: %d", a)
+	}
+	mask := (uint64(1) << k) - 1
+	if b > mask {
+		return nil, fmt.Errorf("affine: offset out of range for k=%d: %d", k, b)
+	}
+	// Newton iteration for the inverse of an odd number mod 2^64; each step
+	// doubles the number of correct low bits (3 -> 6 -> ... -> 96).
+	inv := a
+	for i := 0; i < 5; i++ {
+		inv *= 2 - a*inv
+	}
+	return &affine{k: k, a: a, aInv: inv, b: b, mask: mask}, nil
+}
+
+func (f *affine) DomainBits() uint { return f.k }
+
+// Describe implements Describer.
+func (f *affine) Describe() string {
+	return fmt.Sprintf("affine(k=%d,a=%#x,b=%#x)", f.k, f.a, f.b)
+}
+
+func (f *affine) Obfuscate(x uint64) uint64 {
+	return (f.a*(x&f.mask) + f.b) & f.mask
+}
+
+func (f *affine) Deobfuscate(y uint64) uint64 {
+	return ((y&f.mask - f.b) * f.aInv) & f.mask
+}
+
+// xorMask implements x -> x ^ mask over a k-bit domain.
+type xorMask struct {
+	k    uint
+	m    uint64
+	mask uint64
+}
+
+// NewXorMask creates a k-bit permutation that XORs every value with mask.
+// k must be in [1, 63] and mask must fit in k bits.
+func NewXorMask(k uint, mask uint64) (Obfuscator, error) {
+	if k == 0 || k > 63 {
+		return nil, fmt.Errorf("xormask: k out of range: %d", k)
+	}
+	domain := (uint64(1) << k) - 1
+	if mask > domain {
+		return nil, fmt.Errorf("xormask: mask out of range for k=%d: %#x", k, mask)
+	}
+	return &xorMask{k: k, m: mask, mask: domain}, nil
+}
+
+func (f *xorMask) DomainBits() uint { return f.k }
+
+// Describe implements Describer.
+func (f *xorMask) Describe() string {
+	return fmt.Sprintf("xormask(k=%d,mask=%#x)", f.k, f.m)
+}
+
+func (f *xorMask) Obfuscate(x uint64) uint64 { return (x ^ f.m) & f.mask }
+
+func (f *xorMask) Deobfuscate(y uint64) uint64 { return (y ^ f.m) & f.mask }
+
+// chain applies its members in order and inverts them in reverse order.
+type chain struct {
+	k   uint
+	obs []Obfuscator
+}
+
+// Chain composes obfuscators into a single permutation. Obfuscate applies
+// obs[0] first; Deobfuscate undoes them in reverse. All members must share
+// the same DomainBits.
+func Chain(obs ...Obfuscator) (Obfuscator, error) {
+	if len(obs) == 0 {
+		return nil, fmt.Errorf("chain: at least one obfuscator required")
+	}
+	for i, ob := range obs {
+		if ob == nil {
+			return nil, fmt.Errorf("chain: obfuscator %d is nil", i)
+		}
+		if ob.DomainBits() != obs[0].DomainBits() {
+			return nil, fmt.Errorf("chain: obfuscator %d has %d domain bits, want %d", i, ob.DomainBits(), obs[0].DomainBits())
+		}
+	}
+	return &chain{k: obs[0].DomainBits(), obs: append([]Obfuscator(nil), obs...)}, nil
+}
+
+func (c *chain) DomainBits() uint { return c.k }
+
+// Describe implements Describer. Members without Describe appear as "?".
+func (c *chain) Describe() string {
+	parts := make([]string, len(c.obs))
+	for i, ob := range c.obs {
+		parts[i] = "?"
+		if d, ok := ob.(Describer); ok {
+			parts[i] = d.Describe()
+		}
+	}
+	return "chain(" + strings.Join(parts, ",") + ")"
+}
+
+func (c *chain) Obfuscate(x uint64) uint64 {
+	for _, ob := range c.obs {
+		x = ob.Obfuscate(x)
+	}
+	return x
+}
+
+func (c *chain) Deobfuscate(y uint64) uint64 {
+	for i := len(c.obs) - 1; i >= 0; i-- {
+		y = c.obs[i].Deobfuscate(y)
+	}
+	return y
+}
+
+// RangePermutation is a bijection on an arbitrary range [0, n). It is built by
+// cycle-walking a power-of-two Obfuscator: values that land outside the range
+// are permuted again until they fall back inside it.
+type RangePermutation struct {
+	n    uint64
+	base Obfuscator
+}
+
+// NewRangePermutation creates a permutation of [0, n) on top of base.
+// base must cover n (n <= 2^k) without being more than twice as large, which
+// bounds the expected number of cycle-walking steps to below two.
+func NewRangePermutation(n uint64, base Obfuscator) (*RangePermutation, error) {
+	if base == nil {
+		return nil, fmt.Errorf("range: base obfuscator cannot be nil")
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("range: n must be >= 1")
+	}
+	k := base.DomainBits()
+	if k < 64 && n > uint64(1)<<k {
+		return nil, fmt.Errorf("range: n=%d exceeds base domain of %d bits", n, k)
+	}
+	if k > 1 && n <= uint64(1)<<(k-1) {
+		return nil, fmt.Errorf("range: base domain of %d bits is too large for n=%d", k, n)
+	}
+	return &RangePermutation{n: n, base: base}, nil
+}
+
+// Size returns n, the number of values in the permuted range.
+func (p *RangePermutation) Size() uint64 { return p.n }
+
+// Obfuscate maps x in [0, n) to a unique value in [0, n). Inputs outside the
+// range are returned unchanged, since cycle-walking may never terminate for them.
+func (p *RangePermutation) Obfuscate(x uint64) uint64 {
+	if x >= p.n {
+		return x
+	}
+	y := p.base.Obfuscate(x)
+	for y >= p.n {
+		y = p.base.Obfuscate(y)
+	}
+	return y
+}
+
+// Deobfuscate reverses Obfuscate. Like Obfuscate, it returns inputs outside
+// [0, n) unchanged.
+func (p *RangePermutation) Deobfuscate(y uint64) uint64 {
+	if y >= p.n {
+		return y
+	}
+	x := p.base.Deobfuscate(y)
+	for x >= p.n {
+		x = p.base.Deobfuscate(x)
+	}
+	return x
+}
@@ -0,0 +1,186 @@
+package idgen
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// checkBijection exhaustively verifies ob is a permutation of its (small) domain.
+func checkBijection(t *testing.T, ob Obfuscator) {
+	t.Helper()
+	mask := (uint64(1) << ob.DomainBits()) - 1
+	seen := make([]bool, mask+1)
+	for x := uint64(0); x <= mask; x++ {
+		y := ob.Obfuscate(x)
+		if y > mask {
+			t.Fatalf("y out of range: %d > %d", y, mask)
+		}
+		if seen[y] {
+			t.Fatalf("collision at y=%d", y)
+		}
+		seen[y] = true
+		if x2 := ob.Deobfuscate(y); x2 != x {
+			t.Fatalf("round-trip failed: got %d want %d", x2, x)
+		}
+	}
+}
+
+func TestAffineXorChainBijectionSmallK(t *testing.T) {
+	k := uint(12)
+	aff, err := NewAffine(k, 0x9E37, 0x5A5)
+	if err != nil {
+		t.Fatalf("NewAffine error: %v", err)
+	}
+	xm, err := NewXorMask(k, 0xA5C)
+	if err != nil {
+		t.Fatalf("NewXorMask error: %v", err)
+	}
+	fe, err := NewFeistel(k, 4)
+	if err != nil {
+		t.Fatalf("NewFeistel error: %v", err)
+	}
+	ch, err := Chain(xm, aff, fe)
+	if err != nil {
+		t.Fatalf("Chain error: %v", err)
+	}
+	for name, ob := range map[string]Obfuscator{"affine": aff, "xormask": xm, "chain": ch} {
+		t.Run(name, func(t *testing.T) { checkBijection(t, ob) })
+	}
+}
+
+func TestAffineRoundTripRandom63(t *testing.T) {
+	aff, err := NewAffine(63, 0x9E3779B97F4A7C15, 12345)
+	if err != nil {
+		t.Fatalf("NewAffine error: %v", err)
+	}
+	mask := (uint64(1) << 63) - 1
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 2000; i++ {
+		x := rng.Uint64() & mask
+		if x2 := aff.Deobfuscate(aff.Obfuscate(x)); x2 != x {
+			t.Fatalf("iter %d: round-trip mismatch got=%d want=%d", i, x2, x)
+		}
+	}
+}
+
+func TestPermutationValidation(t *testing.T) {
+	if _, err := NewAffine(12, 4, 0); err == nil {
+		t.Fatal("expected error for even multiplier")
+	}
+	if _, err := NewAffine(12, 3, 1<<12); err == nil {
+		t.Fatal("expected error for offset out of range")
+	}
+	if _, err := NewXorMask(64, 1); err == nil {
+		t.Fatal("expected error for k out of range")
+	}
+	if _, err := NewXorMask(8, 0x100); err == nil {
+		t.Fatal("expected error for mask out of range")
+	}
+	if _, err := Chain(); err == nil {
+		t.Fatal("expected error for empty chain")
+	}
+	a, _ := NewXorMask(8, 1)
+	b, _ := NewXorMask(9, 1)
+	if _, err := Chain(a, b); err == nil {
+		t.Fatal("expected error for domain mismatch")
+	}
+}
+
+func TestRangePermutationBijection(t *testing.T) {
+	base, err := NewFeistel(10, 4)
+	if err != nil {
+		t.Fatalf("NewFeistel error: %v", err)
+	}
+	const n = 1000
+	rp, err := NewRangePermutation(n, base)
+	if err != nil {
+		t.Fatalf("NewRangePermutation error: %v", err)
+	}
+	seen := make([]bool, n)
+	for x := uint64(0); x < n; x++ {
+		y := rp.Obfuscate(x)
+		if y >= n {
+			t.Fatalf("y out of range: %d >= %d", y, n)
+		}
+		if seen[y] {
+			t.Fatalf("collision at y=%d", y)
+		}
+		seen[y] = true
+		if x2 := rp.Deobfuscate(y); x2 != x {
+			t.Fatalf("round-trip failed: got %d want %d", x2, x)
+		}
+	}
+
+	if _, err := NewRangePermutation(2000, base); err == nil {
+		t.Fatal("expected error for n larger than base domain")
+	}
+	if _, err := NewRangePermutation(100, base); err == nil {
+		t.Fatal("expected error for base domain much larger than n")
+	}
+}
+
+func TestGeneratorFullWidthReachesEveryCode(t *testing.T) {
+	g, err := New(WithWidth(2), WithFullWidth(), WithEpoch(time.Now()), WithPace(time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	const n = 36 * 36
+	seen := make(map[string]bool, n)
+	for raw := int64(0); raw < n; raw++ {
+		id := g.Format(raw)
+		if seen[id] {
+			t.Fatalf("duplicate code %q for raw=%d", id, raw)
+		}
+		seen[id] = true
+		back, err := g.Parse(id)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", id, err)
+		}
+		if back != raw {
+			t.Fatalf("round-trip mismatch: got %d want %d", back, raw)
+		}
+	}
+	if len(seen) != n {
+		t.Fatalf("reached %d codes, want %d", len(seen), n)
+	}
+}
+
+func TestRangePermutationOutOfRangeInput(t *testing.T) {
+	base, _ := NewFeistel(10, 4)
+	rp, err := NewRangePermutation(1000, base)
+	if err != nil {
+		t.Fatalf("NewRangePermutation error: %v", err)
+	}
+	// Cycle-walking from these may never re-enter [0, n); they must be
+	// returned unchanged rather than looping.
+	for _, x := range []uint64{1000, 1023, 1 << 10, 1<<63 + 5} {
+		if y := rp.Obfuscate(x); y != x {
+			t.Fatalf("Obfuscate(%d) = %d, want unchanged", x, y)
+		}
+		if y := rp.Deobfuscate(x); y != x {
+			t.Fatalf("Deobfuscate(%d) = %d, want unchanged", x, y)
+		}
+	}
+}
+
+func TestGeneratorRangePermutationRejectsOutOfRange(t *testing.T) {
+	base, _ := NewFeistel(10, 4)
+	rp, err := NewRangePermutation(1000, base)
+	if err != nil {
+		t.Fatalf("NewRangePermutation error: %v", err)
+	}
+	g, err := New(WithWidth(2), WithRangePermutation(rp), WithEpoch(time.Now()), WithPace(time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if _, err := g.Parse("zz"); err == nil {
+		t.Fatal("expected error for code outside the permuted range")
+	}
+	if _, err := New(WithWidth(1), WithRangePermutation(rp)); err == nil {
+		t.Fatal("expected error for width too small for range")
+	}
+	if _, err := New(WithWidth(13), WithFullWidth()); err == nil {
+		t.Fatal("expected error for width too large for full-width range")
+	}
+}
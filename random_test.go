@@ -0,0 +1,116 @@
+package idgen
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestWithRandomBits(t *testing.T) {
+	g, err := New(WithWidth(10), WithRandomBits(14))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if want := g.Epoch().Add(time.Duration(1<<(51-14)-1) * time.Millisecond); !g.Horizon().Equal(want) {
+		t.Fatalf("Horizon() = %v, want %v", g.Horizon(), want)
+	}
+	before := time.Now().UTC().Add(-time.Millisecond)
+	var prev int64
+	randoms := map[uint64]bool{}
+	for i := 0; i < 50; i++ {
+		raw := g.Generate()
+		if raw <= prev {
+			t.Fatalf("raw not increasing: %d after %d", raw, prev)
+		}
+		prev = raw
+		id := g.Format(raw)
+		back, err := g.Parse(id)
+		if err != nil || back != raw {
+			t.Fatalf("Parse(%q) = %d, %v; want %d", id, back, err, raw)
+		}
+		ts, err := g.TimestampFromID(id)
+		if err != nil || ts.Before(before) || ts.After(time.Now().UTC()) {
+			t.Fatalf("TimestampFromID(%q) = %v, %v; want about now", id, ts, err)
+		}
+		_, r := g.Split(raw)
+		randoms[r] = true
+	}
+	if len(randoms) < 45 {
+		t.Fatalf("only %d distinct random parts in 50 IDs", len(randoms))
+	}
+
+	f, err := g.Decode(g.Format(prev))
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	tick, r := g.Split(prev)
+	if f[TickField] != uint64(tick) || f[RandomField] != r {
+		t.Fatalf("Decode = %v, want tick=%d rand=%d", f, tick, r)
+	}
+	if raw, err := g.Compose(f); err != nil || raw != prev {
+		t.Fatalf("Compose(%v) = %d, %v; want %d", f, raw, err, prev)
+	}
+}
+
+func TestWithEntropySource(t *testing.T) {
+	src := bytes.NewReader(bytes.Repeat([]byte{0xff}, 8))
+	g, err := New(WithWidth(9), WithRandomBits(8), WithEntropySource(src))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if _, r := g.Split(g.Generate()); r != 0xff {
+		t.Fatalf("random part = %#x, want 0xff", r)
+	}
+	// The exhausted source falls back to crypto/rand instead of panicking.
+	seen := map[uint64]bool{}
+	for i := 0; i < 16; i++ {
+		_, r := g.Split(g.Generate())
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("random parts after the source failed = %v, want crypto/rand values", seen)
+	}
+}
+
+func TestWithEntropySourceFailureLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	g, err := New(WithWidth(9), WithRandomBits(8), WithEntropySource(iotest.ErrReader(errors.New("boom"))), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	g.Generate()
+	g.Generate()
+	if n := strings.Count(buf.String(), "entropy source failed"); n != 1 {
+		t.Fatalf("logged %d entropy failures, want 1:\n%s", n, buf.String())
+	}
+}
+
+func TestWithRandomBitsValidation(t *testing.T) {
+	l, _ := ParseLayout("tick:33,node:8")
+	cases := map[string][]Option{
+		"zero":      {WithRandomBits(0)},
+		"no tick":   {WithBits(20), WithRandomBits(20)},
+		"layout":    {WithLayout(l), WithRandomBits(4)},
+		"fullwidth": {WithFullWidth(), WithRandomBits(4)},
+	}
+	for name, opts := range cases {
+		if _, err := New(opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := NewSharded(2, WithRandomBits(4)); err == nil {
+		t.Error("expected error combining random bits with sharding")
+	}
+	g, _ := NewFromConfig(Config{Width: 10, RandomBits: 12})
+	if c, err := g.Config(); err != nil || c.RandomBits != 12 {
+		t.Fatalf("Config() = %+v, %v; want random_bits 12", c, err)
+	}
+	if plain, _ := New(); plain.Fingerprint() == g.Fingerprint() {
+		t.Fatal("random bits should change the fingerprint")
+	}
+}
@@ -0,0 +1,147 @@
+package idgen
+
+import (
+	"errors"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Sharded spreads generation across several independently paced Generators.
+//
+// The raw value is split into a tick field (high bits) and a shard index (low
+// shardBits bits). Each shard enforces the configured pace on its own, so
+// aggregate throughput scales with the number of shards while every ID still
+// decodes to its timestamp and originating shard. The tick field loses
+// shardBits bits of horizon compared to a plain Generator of the same width.
+type Sharded struct {
+	base      *Generator // shared codec/obfuscator for Format/Parse
+	shards    []*Generator
+	shardBits uint
+	next      atomic.Uint64
+
+	strict bool         // see WithStrictOrdering
+	last   atomic.Int64 // highest value returned, in strict mode
+}
+
+// NewSharded constructs a Sharded generator with n shards using the same options
+// as New. n must be a power of two and leave at least one bit for the tick.
+func NewSharded(n int, opts ...Option) (*Sharded, error) {
+	if n < 1 || n&(n-1) != 0 {
+		return nil, errors.New("shard count must be a power of two >= 1")
+	}
+	base, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if base.layout != nil {
+		return nil, errors.New("layout cannot be combined with sharding")
+	}
+	if base.randBits > 0 {
+		return nil, errors.New("random bits cannot be combined with sharding")
+	}
+	if base.derived {
+		return nil, errors.New("derived IDs cannot be combined with sharding")
+	}
+	if base.hlc {
+		return nil, errors.New("hlc cannot be combined with sharding")
+	}
+	shardBits := uint(bits.TrailingZeros(uint(n)))
+	if shardBits >= base.bits {
+		return nil, errors.New("too many shards for selected bits")
+	}
+	// The shard index costs shardBits of horizon.
+	if err := base.checkHorizon(base.horizonTick >> shardBits); err != nil {
+		return nil, err
+	}
+	s := &Sharded{
+		base:      base,
+		shards:    make([]*Generator, n),
+		shardBits: shardBits,
+		strict:    base.strict,
+	}
+	for i := range s.shards {
+		s.shards[i] = &Generator{
+			epochMS: base.epochMS,
+			pace:    base.pace,
+			bits:    base.bits,
+			width:   base.width,
+			ob:      base.ob,
+			obs:     base.obs,
+			now:     base.now,
+			// Each shard only owns the high bits of the raw value.
+			horizonTick: base.horizonTick >> shardBits,
+		}
+	}
+	return s, nil
+}
+
+// advance raises the strict-mode high-water mark to raw, reporting false if a
+// value at least as large was already returned.
+func (s *Sharded) advance(raw int64) bool {
+	for {
+		last := s.last.Load()
+		if raw <= last {
+			return false
+		}
+		if s.last.CompareAndSwap(last, raw) {
+			return true
+		}
+	}
+}
+
+// Shards returns the number of internal generators.
+func (s *Sharded) Shards() int { return len(s.shards) }
+
+// Generate returns a raw value composed of a paced tick and the shard index.
+// Calls are distributed across shards round-robin. Values whose formatted ID
+// hits the blocklist are skipped, as with Generator.Generate.
+//
+// Values are unique but, since shards advance independently, a later call may
+// return a smaller value than an earlier one. WithStrictOrdering makes
+// Generate linearizable by retrying until the value exceeds every value
+// already returned.
+func (s *Sharded) Generate() int64 {
+	for {
+		i := int((s.next.Add(1) - 1) % uint64(len(s.shards)))
+		tick := s.shards[i].Generate()
+		raw := tick<<s.shardBits | int64(i)
+		if s.base.blocked(raw) {
+			continue
+		}
+		if !s.strict || s.advance(raw) {
+			return raw
+		}
+	}
+}
+
+// Horizon returns the UTC timestamp of the last tick that encodes without
+// wrapping; it is shardBits bits earlier than for a plain Generator.
+func (s *Sharded) Horizon() time.Time {
+	return s.base.tickTime(s.shards[0].horizonTick)
+}
+
+// Format converts a raw value into the same fixed-width base36 form as Generator.Format.
+func (s *Sharded) Format(raw int64) string { return s.base.Format(raw) }
+
+// Parse reverses Format and returns the raw value (tick and shard combined).
+func (s *Sharded) Parse(id string) (int64, error) { return s.base.Parse(id) }
+
+// ShardFromRaw returns the shard index encoded in a raw value.
+func (s *Sharded) ShardFromRaw(raw int64) int {
+	return int(raw & (int64(1)<<s.shardBits - 1))
+}
+
+// TimestampFromRaw converts a raw value to time.Time in UTC, ignoring the shard index.
+func (s *Sharded) TimestampFromRaw(raw int64) time.Time {
+	return s.base.TimestampFromRaw(raw >> s.shardBits)
+}
+
+// Decode parses a formatted ID and returns its UTC timestamp and shard index.
+func (s *Sharded) Decode(id string) (time.Time, int, error) {
+	raw, err := s.Parse(id)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return s.TimestampFromRaw(raw), s.ShardFromRaw(raw), nil
+}
@@ -0,0 +1,64 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedDecodeAndUniqueness(t *testing.T) {
+	s, err := NewSharded(4)
+	if err != nil {
+		t.Fatalf("NewSharded error: %v", err)
+	}
+	before := time.Now().UTC().Truncate(time.Millisecond)
+
+	const workers = 32
+	vals := make([]int64, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			vals[i] = s.Generate()
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, workers)
+	shardsUsed := make(map[int]bool)
+	for _, raw := range vals {
+		if seen[raw] {
+			t.Fatalf("duplicate sharded value %d in %v", raw, vals)
+		}
+		seen[raw] = true
+
+		id := s.Format(raw)
+		when, shard, err := s.Decode(id)
+		if err != nil {
+			t.Fatalf("Decode(%q) error: %v", id, err)
+		}
+		if shard != s.ShardFromRaw(raw) {
+			t.Fatalf("shard mismatch for %q: got %d want %d", id, shard, s.ShardFromRaw(raw))
+		}
+		if when.Before(before) || when.After(time.Now().Add(time.Second)) {
+			t.Fatalf("timestamp %v out of range (started %v)", when, before)
+		}
+		shardsUsed[shard] = true
+	}
+	if len(shardsUsed) != s.Shards() {
+		t.Fatalf("round-robin used %d shards, want %d", len(shardsUsed), s.Shards())
+	}
+}
+
+func TestShardedValidation(t *testing.T) {
+	if _, err := NewSharded(0); err == nil {
+		t.Fatal("expected error for 0 shards")
+	}
+	if _, err := NewSharded(3); err == nil {
+		t.Fatal("expected error for non power of two shards")
+	}
+	if _, err := NewSharded(4, WithWidth(1), WithBits(2)); err == nil {
+		t.Fatal("expected error when shard bits consume the domain")
+	}
+}
@@ -0,0 +1,202 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IDSource abstracts where IDs come from, so application code can switch
+// between a local Generator, a remote allocator or a fake without changes.
+// Decorators (NewRetrySource, NewPooledSource, NewMetricsSource) wrap any
+// IDSource and can be stacked.
+type IDSource interface {
+	// Next returns one new ID.
+	Next(ctx context.Context) (ID, error)
+	// NextN returns n new IDs in issue order.
+	NextN(ctx context.Context, n int) ([]ID, error)
+}
+
+var _ IDSource = (*Generator)(nil)
+
+// Next implements IDSource. It fails only if ctx is already done.
+func (g *Generator) Next(ctx context.Context) (ID, error) {
+	if err := ctx.Err(); err != nil {
+		return ID{}, err
+	}
+	return g.GenerateID(), nil
+}
+
+// NextN implements IDSource. Generation stops early if ctx is done. With
+// WithStrictOrdering no other call is served until the batch is complete.
+func (g *Generator) NextN(ctx context.Context, n int) ([]ID, error) {
+	if n < 1 {
+		return nil, errors.New("n must be >= 1")
+	}
+	if g.strict {
+		g.strictMu.Lock()
+		defer g.strictMu.Unlock()
+	}
+	ids := make([]ID, n)
+	for i := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ids[i] = ID{raw: g.generate(), g: g}
+	}
+	return ids, nil
+}
+
+// stale reports whether id predates the newest value g has issued, so a
+// strictly ordered consumer must not hand it out any more.
+func (g *Generator) stale(id ID) bool {
+	if !g.strict || id.g != g {
+		return false
+	}
+	// The tick, plus the logical counter in HLC mode.
+	return id.raw>>(g.tickShift-g.hlcBits) < g.lastTick.Load()
+}
+
+// retrySource retries failed calls with exponential backoff.
+type retrySource struct {
+	src      IDSource
+	attempts int
+	backoff  time.Duration
+}
+
+// NewRetrySource wraps src so failed calls are retried up to attempts times in
+// total, sleeping backoff before the first retry and doubling it after each.
+// Context errors are returned immediately.
+func NewRetrySource(src IDSource, attempts int, backoff time.Duration) (IDSource, error) {
+	if src == nil {
+		return nil, errors.New("source cannot be nil")
+	}
+	if attempts < 1 {
+		return nil, errors.New("attempts must be >= 1")
+	}
+	return &retrySource{src: src, attempts: attempts, backoff: backoff}, nil
+}
+
+func (r *retrySource) Next(ctx context.Context) (ID, error) {
+	var id ID
+	err := r.do(ctx, func() (err error) {
+		id, err = r.src.Next(ctx)
+		return err
+	})
+	return id, err
+}
+
+func (r *retrySource) NextN(ctx context.Context, n int) ([]ID, error) {
+	var ids []ID
+	err := r.do(ctx, func() (err error) {
+		ids, err = r.src.NextN(ctx, n)
+		return err
+	})
+	return ids, err
+}
+
+func (r *retrySource) do(ctx context.Context, call func() error) error {
+	wait := r.backoff
+	var err error
+	for i := 0; i < r.attempts; i++ {
+		if i > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+			wait *= 2
+		}
+		if err = call(); err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// pooledSource hands out IDs from a prefetched buffer.
+type pooledSource struct {
+	src  IDSource
+	size int
+
+	mu   sync.Mutex
+	pool []ID
+}
+
+// NewPooledSource wraps src so IDs are fetched in blocks of size and served
+// from a local buffer, which amortizes round trips to a remote allocator.
+// Pooled IDs carry the time they were fetched, not the time they were handed out.
+// If src is a Generator created with WithStrictOrdering, buffered IDs are
+// discarded once the generator has issued newer IDs to other callers.
+func NewPooledSource(src IDSource, size int) (IDSource, error) {
+	if src == nil {
+		return nil, errors.New("source cannot be nil")
+	}
+	if size < 1 {
+		return nil, errors.New("pool size must be >= 1")
+	}
+	return &pooledSource{src: src, size: size}, nil
+}
+
+func (p *pooledSource) Next(ctx context.Context) (ID, error) {
+	ids, err := p.NextN(ctx, 1)
+	if err != nil {
+		return ID{}, err
+	}
+	return ids[0], nil
+}
+
+func (p *pooledSource) NextN(ctx context.Context, n int) ([]ID, error) {
+	if n < 1 {
+		return nil, errors.New("n must be >= 1")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if g, ok := p.src.(*Generator); ok && len(p.pool) > 0 && g.stale(p.pool[len(p.pool)-1]) {
+		// The generator issued IDs after our newest one; drop ours to keep order.
+		p.pool = p.pool[:0]
+	}
+	if len(p.pool) < n {
+		// Refill enough for this request plus a full block for later callers.
+		more, err := p.src.NextN(ctx, n-len(p.pool)+p.size)
+		if err != nil {
+			return nil, err
+		}
+		p.pool = append(p.pool, more...)
+	}
+	ids := append([]ID(nil), p.pool[:n]...)
+	p.pool = p.pool[n:]
+	return ids, nil
+}
+
+// metricsSource reports every call to a callback.
+type metricsSource struct {
+	src    IDSource
+	record func(n int, took time.Duration, err error)
+}
+
+// NewMetricsSource wraps src so every call reports the number of IDs
+// requested, its duration and its error (nil on success) to record.
+func NewMetricsSource(src IDSource, record func(n int, took time.Duration, err error)) (IDSource, error) {
+	if src == nil || record == nil {
+		return nil, errors.New("source and record func are required")
+	}
+	return &metricsSource{src: src, record: record}, nil
+}
+
+func (m *metricsSource) Next(ctx context.Context) (ID, error) {
+	start := time.Now()
+	id, err := m.src.Next(ctx)
+	m.record(1, time.Since(start), err)
+	return id, err
+}
+
+func (m *metricsSource) NextN(ctx context.Context, n int) ([]ID, error) {
+	start := time.Now()
+	ids, err := m.src.NextN(ctx, n)
+	m.record(n, time.Since(start), err)
+	return ids, err
+}
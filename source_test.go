@@ -0,0 +1,138 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGeneratorIDSource(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx := context.Background()
+	ids, err := g.NextN(ctx, 3)
+	if err != nil {
+		t.Fatalf("NextN error: %v", err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i].Raw() <= ids[i-1].Raw() {
+			t.Fatalf("NextN not increasing: %v", ids)
+		}
+	}
+	if _, err := g.NextN(ctx, 0); err == nil {
+		t.Fatal("expected error for n=0")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := g.Next(cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Next(cancelled) error = %v, want context.Canceled", err)
+	}
+}
+
+// flakySource fails the first failures calls, then delegates to g.
+type flakySource struct {
+	g        *Generator
+	failures int
+	calls    int
+}
+
+var errFlaky = errors.New("flaky")
+
+func (f *flakySource) Next(ctx context.Context) (ID, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return ID{}, errFlaky
+	}
+	return f.g.Next(ctx)
+}
+
+func (f *flakySource) NextN(ctx context.Context, n int) ([]ID, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errFlaky
+	}
+	return f.g.NextN(ctx, n)
+}
+
+func TestRetrySource(t *testing.T) {
+	g, _ := New()
+	ctx := context.Background()
+
+	flaky := &flakySource{g: g, failures: 2}
+	src, err := NewRetrySource(flaky, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewRetrySource error: %v", err)
+	}
+	if _, err := src.Next(ctx); err != nil || flaky.calls != 3 {
+		t.Fatalf("Next = %v after %d calls, want success after 3", err, flaky.calls)
+	}
+
+	flaky = &flakySource{g: g, failures: 5}
+	src, _ = NewRetrySource(flaky, 3, time.Millisecond)
+	if _, err := src.NextN(ctx, 2); !errors.Is(err, errFlaky) || flaky.calls != 3 {
+		t.Fatalf("NextN = %v after %d calls, want errFlaky after 3", err, flaky.calls)
+	}
+	if _, err := NewRetrySource(g, 0, 0); err == nil {
+		t.Fatal("expected error for zero attempts")
+	}
+}
+
+func TestPooledSource(t *testing.T) {
+	g, _ := New()
+	ctx := context.Background()
+	counting := &flakySource{g: g}
+	src, err := NewPooledSource(counting, 4)
+	if err != nil {
+		t.Fatalf("NewPooledSource error: %v", err)
+	}
+	var got []ID
+	for i := 0; i < 5; i++ {
+		id, err := src.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next error: %v", err)
+		}
+		got = append(got, id)
+	}
+	// First call fetches 1+4, which covers all five calls.
+	if counting.calls != 1 {
+		t.Fatalf("underlying calls = %d, want 1", counting.calls)
+	}
+	more, err := src.NextN(ctx, 3)
+	if err != nil || len(more) != 3 {
+		t.Fatalf("NextN = %d ids, %v", len(more), err)
+	}
+	got = append(got, more...)
+	for i := 1; i < len(got); i++ {
+		if got[i].Raw() <= got[i-1].Raw() {
+			t.Fatalf("pooled ids not increasing at %d", i)
+		}
+	}
+}
+
+func TestMetricsSource(t *testing.T) {
+	g, _ := New()
+	var calls, ids int
+	var lastErr error
+	src, err := NewMetricsSource(&flakySource{g: g, failures: 1}, func(n int, took time.Duration, err error) {
+		calls++
+		ids += n
+		lastErr = err
+	})
+	if err != nil {
+		t.Fatalf("NewMetricsSource error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := src.Next(ctx); !errors.Is(err, errFlaky) || !errors.Is(lastErr, errFlaky) {
+		t.Fatalf("first Next error = %v, recorded %v", err, lastErr)
+	}
+	if _, err := src.NextN(ctx, 2); err != nil || lastErr != nil {
+		t.Fatalf("NextN error = %v, recorded %v", err, lastErr)
+	}
+	if calls != 2 || ids != 3 {
+		t.Fatalf("recorded calls=%d ids=%d, want 2 and 3", calls, ids)
+	}
+}
@@ -0,0 +1,140 @@
+package idgen
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Candidate is a corrected ID proposed by Suggest.
+type Candidate struct {
+	ID   string    // canonical formatted ID
+	Raw  int64     // decoded raw tick
+	Time time.Time // UTC timestamp of the raw tick
+	// Cost ranks candidates: lower is more likely. Each confusable-glyph swap
+	// costs 1, each adjacent transposition 2 and any other substitution 3.
+	Cost int
+}
+
+const (
+	costConfusable    = 1
+	costTransposition = 2
+	costSubstitution  = 3
+)
+
+// confusables lists characters commonly misread as one another.
+var confusables = map[byte]string{
+	'0': "o", 'o': "0",
+	'1': "li", 'l': "1i", 'i': "1l",
+	'5': "s", 's': "5",
+}
+
+// Suggest proposes corrections for a mistyped ID by applying up to maxEdits
+// single-character edits: confusable-glyph swaps (0/o, 1/l/i, 5/s), adjacent
+// transpositions and arbitrary substitutions. Only candidates that decode to
+// canonical IDs with timestamps inside the plausible window (see
+// WithPlausibleWindow) are returned, ordered by Cost. If id is itself valid
+// and plausible it is returned first with Cost 0. maxEdits is capped at 2.
+func (g *Generator) Suggest(id string, maxEdits int) []Candidate {
+	s := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(id), "-", ""))
+	if len(s) != g.width || maxEdits < 0 {
+		return nil
+	}
+	for i := 0; i < len(s); i++ {
+		if !isAlnum(s[i]) {
+			return nil
+		}
+	}
+	if maxEdits > 2 {
+		maxEdits = 2
+	}
+
+	// Breadth-first over edit distance, keeping the cheapest cost per string.
+	costs := map[string]int{s: 0}
+	frontier := []string{s}
+	for e := 0; e < maxEdits; e++ {
+		var next []string
+		for _, cur := range frontier {
+			base := costs[cur]
+			visit := func(b []byte, cost int) {
+				c := string(b)
+				old, seen := costs[c]
+				if seen && old <= base+cost {
+					return
+				}
+				if !seen {
+					next = append(next, c)
+				}
+				costs[c] = base + cost
+			}
+			b := []byte(cur)
+			for i := 0; i < len(b); i++ {
+				orig := b[i]
+				for j := 0; j < len(g.alpha.digits); j++ {
+					r := g.alpha.digits[j]
+					if r == orig {
+						continue
+					}
+					cost := costSubstitution
+					if strings.IndexByte(confusables[orig], r) >= 0 {
+						cost = costConfusable
+					}
+					b[i] = r
+					visit(b, cost)
+				}
+				b[i] = orig
+				if i+1 < len(b) && b[i] != b[i+1] {
+					b[i], b[i+1] = b[i+1], b[i]
+					visit(b, costTransposition)
+					b[i], b[i+1] = b[i+1], b[i]
+				}
+			}
+		}
+		frontier = next
+	}
+
+	from, to := g.plausibleFrom, g.plausibleTo
+	if from.IsZero() {
+		from = time.UnixMilli(g.epochMS)
+	}
+	if to.IsZero() {
+		to = g.now()
+	}
+	var out []Candidate
+	for c, cost := range costs {
+		raw, formatted, ok := g.decodeCanonical(c)
+		if !ok {
+			continue
+		}
+		when := g.TimestampFromRaw(raw)
+		if when.Before(from) || when.After(to) {
+			continue
+		}
+		out = append(out, Candidate{ID: formatted, Raw: raw, Time: when, Cost: cost})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost != out[j].Cost {
+			return out[i].Cost < out[j].Cost
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// decodeCanonical decodes an undashed lowercase code and reports whether
+// Format would produce exactly that code for the decoded raw value.
+func (g *Generator) decodeCanonical(plain string) (int64, string, bool) {
+	v, err := decodeDigits(g.alpha, plain)
+	if err != nil {
+		return 0, "", false
+	}
+	raw, err := g.deobfuscate(v)
+	if err != nil {
+		return 0, "", false
+	}
+	formatted := g.Format(raw)
+	if strings.ReplaceAll(formatted, "-", "") != plain {
+		return 0, "", false
+	}
+	return raw, formatted, true
+}
@@ -0,0 +1,99 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuggestRecoversMistypedID(t *testing.T) {
+	now := time.Now()
+	g, err := New(WithPlausibleWindow(now.Add(-time.Minute), now.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	raw := g.Generate()
+	id := g.Format(raw)
+	plain := strings.ReplaceAll(id, "-", "")
+
+	// Valid input comes back first with zero cost.
+	got := g.Suggest(id, 0)
+	if len(got) != 1 || got[0].ID != id || got[0].Raw != raw || got[0].Cost != 0 {
+		t.Fatalf("Suggest(valid, 0) = %+v, want only %q", got, id)
+	}
+
+	// Single substitution at each position, and one transposition.
+	for i := 0; i < len(plain); i++ {
+		b := []byte(plain)
+		want := costSubstitution
+		if alt, ok := confusables[b[i]]; ok {
+			b[i] = alt[0]
+			want = costConfusable
+		} else if b[i] == 'z' {
+			b[i] = 'y'
+		} else {
+			b[i] = 'z'
+		}
+		assertSuggests(t, g, strings.ToUpper(string(b)), id, want)
+	}
+	for i := 0; i+1 < len(plain); i++ {
+		if plain[i] == plain[i+1] {
+			continue
+		}
+		b := []byte(plain)
+		b[i], b[i+1] = b[i+1], b[i]
+		assertSuggests(t, g, string(b), id, costTransposition)
+		break
+	}
+}
+
+func assertSuggests(t *testing.T, g *Generator, typed, want string, cost int) {
+	t.Helper()
+	got := g.Suggest(typed, 1)
+	for _, c := range got {
+		if c.ID == want {
+			if c.Cost != cost {
+				t.Fatalf("Suggest(%q): %q has cost %d, want %d", typed, want, c.Cost, cost)
+			}
+			return
+		}
+	}
+	t.Fatalf("Suggest(%q) = %+v, missing %q", typed, got, want)
+}
+
+func TestSuggestRejectsImplausibleAndMalformed(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// A code far in the future is outside the default [epoch, now] window.
+	future := g.Format(g.Generate() + int64(365*24*time.Hour/time.Millisecond))
+	if got := g.Suggest(future, 0); len(got) != 0 {
+		t.Fatalf("Suggest(future, 0) = %+v, want none", got)
+	}
+	if got := g.Suggest("abc", 1); got != nil {
+		t.Fatalf("Suggest(wrong length) = %+v, want nil", got)
+	}
+	if got := g.Suggest("abcd-efg!", 1); got != nil {
+		t.Fatalf("Suggest(invalid char) = %+v, want nil", got)
+	}
+	if _, err := New(WithPlausibleWindow(time.Now(), time.Now().Add(-time.Hour))); err == nil {
+		t.Fatal("expected error for inverted plausible window")
+	}
+}
+
+func TestSuggestDefaultWindowUsesClock(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	g, err := New(WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	past := g.Format(int64(now.Add(-time.Hour).Sub(g.Epoch()) / time.Millisecond))
+	future := g.Format(int64(now.Add(time.Hour).Sub(g.Epoch()) / time.Millisecond))
+	if got := g.Suggest(past, 0); len(got) != 1 {
+		t.Fatalf("Suggest(%q) = %+v, want it inside the window ending at the clock", past, got)
+	}
+	if got := g.Suggest(future, 0); len(got) != 0 {
+		t.Fatalf("Suggest(%q) = %+v, want nothing after the injected clock", future, got)
+	}
+}
@@ -0,0 +1,188 @@
+package idgen
+
+import (
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// TenantGenerators lazily creates one Generator per tenant, each obfuscating
+// with a keyed Feistel whose key is derived from a master secret and the
+// tenant ID. Tenants therefore cannot compare their IDs to infer each other's
+// volume, and IDs from one tenant do not parse to meaningful values in another.
+//
+// At most maxTenants generators are kept; beyond that the least recently used
+// idle one is evicted. Pacing state survives eviction: a recreated generator
+// starts after the highest tick any evicted generator issued, so a tenant
+// never sees the same ID twice. Generators are only reachable through the
+// manager, which pins a tenant while its Generate call is in flight.
+type TenantGenerators struct {
+	secret []byte
+	opts   []Option
+	max    int
+	obBits uint // domain of the keyed obfuscator
+	rounds int
+
+	mu      sync.Mutex
+	lru     *list.List // front is most recently used; values are *tenantEntry
+	tenants map[string]*list.Element
+	floor   int64 // highest tick issued by an evicted generator
+}
+
+type tenantEntry struct {
+	tenant string
+	g      *Generator
+	inUse  int // calls to Generate in flight; such entries are not evicted
+}
+
+// NewTenantGenerators returns a manager that builds tenant generators from
+// opts (the same options as New) plus a per-tenant keyed obfuscator. The
+// secret must be at least 16 bytes. Options that set their own obfuscator or
+// range permutation are rejected; WithFullWidth is supported.
+func NewTenantGenerators(secret []byte, maxTenants int, opts ...Option) (*TenantGenerators, error) {
+	if len(secret) < 16 {
+		return nil, errors.New("tenant secret must be at least 16 bytes")
+	}
+	if maxTenants < 1 {
+		return nil, errors.New("max tenants must be >= 1")
+	}
+	base, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := base.ob.(*feistel); !ok || f.keyed || (base.rp != nil && !base.fullWidth) {
+		return nil, errors.New("tenant generators derive their own obfuscator")
+	}
+	return &TenantGenerators{
+		secret:  append([]byte(nil), secret...),
+		opts:    append([]Option(nil), opts...),
+		max:     maxTenants,
+		obBits:  base.ob.DomainBits(),
+		rounds:  base.rounds,
+		lru:     list.New(),
+		tenants: make(map[string]*list.Element),
+	}, nil
+}
+
+// entry returns tenant's cache entry, creating it and evicting the least
+// recently used idle entries as needed; t.mu must be held.
+func (t *TenantGenerators) entry(tenant string) (*tenantEntry, error) {
+	if tenant == "" {
+		return nil, errors.New("tenant cannot be empty")
+	}
+	if e, ok := t.tenants[tenant]; ok {
+		t.lru.MoveToFront(e)
+		return e.Value.(*tenantEntry), nil
+	}
+	g, err := t.newGenerator(tenant)
+	if err != nil {
+		return nil, err
+	}
+	te := &tenantEntry{tenant: tenant, g: g}
+	t.tenants[tenant] = t.lru.PushFront(te)
+	for e := t.lru.Back(); e != nil && t.lru.Len() > t.max; {
+		prev := e.Prev()
+		if old := e.Value.(*tenantEntry); old.inUse == 0 && old != te {
+			t.lru.Remove(e)
+			delete(t.tenants, old.tenant)
+			t.floor = max(t.floor, old.g.lastTick.Load())
+		}
+		e = prev
+	}
+	return te, nil
+}
+
+// newGenerator builds tenant's generator; t.mu must be held.
+func (t *TenantGenerators) newGenerator(tenant string) (*Generator, error) {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(tenant))
+	key := mac.Sum(nil)
+	ob, err := NewKeyedFeistel(t.obBits, t.rounds, key)
+	if err != nil {
+		return nil, err
+	}
+	g, err := New(append(t.opts[:len(t.opts):len(t.opts)], WithObfuscation(ob))...)
+	if err != nil {
+		return nil, err
+	}
+	g.lastTick.Store(t.floor)
+	return g, nil
+}
+
+// Len returns the number of tenants currently cached.
+func (t *TenantGenerators) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lru.Len()
+}
+
+// Generate returns a new formatted ID for tenant. The tenant is not evicted
+// while the call is in flight.
+func (t *TenantGenerators) Generate(tenant string) (string, error) {
+	t.mu.Lock()
+	e, err := t.entry(tenant)
+	if err != nil {
+		t.mu.Unlock()
+		return "", err
+	}
+	e.inUse++
+	t.mu.Unlock()
+
+	id := e.g.Format(e.g.Generate())
+
+	t.mu.Lock()
+	e.inUse--
+	t.mu.Unlock()
+	return id, nil
+}
+
+// Parse decodes id with tenant's obfuscator. It neither caches the tenant nor
+// refreshes its recency, so parsing IDs of unknown tenants cannot evict
+// active ones.
+func (t *TenantGenerators) Parse(tenant, id string) (int64, error) {
+	g, err := t.codec(tenant)
+	if err != nil {
+		return 0, err
+	}
+	return g.Parse(id)
+}
+
+// codec returns tenant's cached generator, or a fresh one that is not added
+// to the cache.
+func (t *TenantGenerators) codec(tenant string) (*Generator, error) {
+	if tenant == "" {
+		return nil, errors.New("tenant cannot be empty")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.tenants[tenant]; ok {
+		return e.Value.(*tenantEntry).g, nil
+	}
+	return t.newGenerator(tenant)
+}
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying tenant, for use with
+// TenantGenerators.ParseContext.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored by ContextWithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// ParseContext is like Parse for the tenant carried by ctx.
+func (t *TenantGenerators) ParseContext(ctx context.Context, id string) (int64, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return 0, errors.New("no tenant in context")
+	}
+	return t.Parse(tenant, id)
+}
@@ -0,0 +1,136 @@
+package idgen
+
+import (
+	"context"
+	"testing"
+)
+
+var testSecret = []byte("0123456789abcdef-master-secret")
+
+// tenantGenerator returns tenant's cached generator, creating it like
+// Generate does.
+func tenantGenerator(t *testing.T, tg *TenantGenerators, tenant string) *Generator {
+	t.Helper()
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	e, err := tg.entry(tenant)
+	if err != nil {
+		t.Fatalf("entry(%q) error: %v", tenant, err)
+	}
+	return e.g
+}
+
+func TestKeyedFeistel(t *testing.T) {
+	a, err := NewKeyedFeistel(12, 4, []byte("tenant-a"))
+	if err != nil {
+		t.Fatalf("NewKeyedFeistel error: %v", err)
+	}
+	checkBijection(t, a)
+	b, _ := NewKeyedFeistel(12, 4, []byte("tenant-b"))
+	same := 0
+	for x := uint64(0); x < 1<<12; x++ {
+		if a.Obfuscate(x) == b.Obfuscate(x) {
+			same++
+		}
+	}
+	if same > 32 {
+		t.Fatalf("keys agree on %d of 4096 inputs", same)
+	}
+	if d := a.(Describer).Describe(); d != "feistel(k=12,rounds=4,keyed)" {
+		t.Fatalf("Describe() = %q", d)
+	}
+}
+
+func TestTenantGenerators(t *testing.T) {
+	tg, err := NewTenantGenerators(testSecret, 2)
+	if err != nil {
+		t.Fatalf("NewTenantGenerators error: %v", err)
+	}
+	ga := tenantGenerator(t, tg, "acme")
+	gb := tenantGenerator(t, tg, "globex")
+	if ga.Format(1000) == gb.Format(1000) {
+		t.Fatal("tenants should format the same raw value differently")
+	}
+	if ga.Fingerprint() == gb.Fingerprint() {
+		t.Fatal("tenants should have different fingerprints")
+	}
+
+	id, err := tg.Generate("acme")
+	if err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	raw, err := tg.ParseContext(ContextWithTenant(context.Background(), "acme"), id)
+	if err != nil || ga.Format(raw) != id {
+		t.Fatalf("ParseContext(%q) = %d, %v", id, raw, err)
+	}
+	if other, _ := tg.Parse("globex", id); other == raw {
+		t.Fatal("another tenant should not decode the same raw value")
+	}
+	if _, err := tg.ParseContext(context.Background(), id); err == nil {
+		t.Fatal("expected error without a tenant in context")
+	}
+
+	// Touch acme so globex is least recently used; a third tenant evicts it.
+	tenantGenerator(t, tg, "acme")
+	tenantGenerator(t, tg, "initech")
+	if tg.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", tg.Len())
+	}
+	if _, ok := tg.tenants["globex"]; ok {
+		t.Fatal("globex should have been evicted")
+	}
+	gb2 := tenantGenerator(t, tg, "globex")
+	if gb2 == gb || gb2.Format(1000) != gb.Format(1000) {
+		t.Fatal("recreated tenant should be a new generator with the same key")
+	}
+	if gb2.lastTick.Load() < gb.lastTick.Load() {
+		t.Fatal("recreated tenant lost its pacing state")
+	}
+}
+
+func TestTenantParseDoesNotCache(t *testing.T) {
+	tg, err := NewTenantGenerators(testSecret, 2)
+	if err != nil {
+		t.Fatalf("NewTenantGenerators error: %v", err)
+	}
+	id, _ := tg.Generate("acme")
+	tg.Generate("globex")
+	for _, tenant := range []string{"initech", "umbrella", "hooli"} {
+		if _, err := tg.Parse(tenant, id); err != nil {
+			t.Fatalf("Parse(%q) error: %v", tenant, err)
+		}
+	}
+	if _, ok := tg.tenants["acme"]; !ok || tg.Len() != 2 {
+		t.Fatalf("Len() = %d after parsing for unknown tenants, want acme and globex still cached", tg.Len())
+	}
+	if raw, err := tg.Parse("acme", id); err != nil || tenantGenerator(t, tg, "acme").Format(raw) != id {
+		t.Fatalf("Parse(acme, %q) = %d, %v", id, raw, err)
+	}
+	if _, err := tg.Parse("", id); err == nil {
+		t.Fatal("expected error for an empty tenant")
+	}
+}
+
+func TestTenantGeneratorsValidation(t *testing.T) {
+	if _, err := NewTenantGenerators([]byte("short"), 1); err == nil {
+		t.Error("expected error for short secret")
+	}
+	if _, err := NewTenantGenerators(testSecret, 0); err == nil {
+		t.Error("expected error for zero capacity")
+	}
+	ob, _ := NewAffine(41, 3, 1)
+	if _, err := NewTenantGenerators(testSecret, 1, WithObfuscation(ob)); err == nil {
+		t.Error("expected error for a custom obfuscator")
+	}
+	tg, err := NewTenantGenerators(testSecret, 1, WithWidth(8), WithFullWidth())
+	if err != nil {
+		t.Fatalf("full width: %v", err)
+	}
+	id, err := tg.Generate("acme")
+	if err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	if _, err := tg.Parse("acme", id); err != nil {
+		t.Fatalf("Parse(%q) error: %v", id, err)
+	}
+}
@@ -0,0 +1,455 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Uint128 is an unsigned 128-bit integer used by the wide (128-bit) IDs.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+func (u Uint128) lsh(n uint) Uint128 {
+	switch {
+	case n == 0:
+		return u
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Hi: u.Lo << (n - 64)}
+	}
+	return Uint128{Hi: u.Hi<<n | u.Lo>>(64-n), Lo: u.Lo << n}
+}
+
+func (u Uint128) rsh(n uint) Uint128 {
+	switch {
+	case n == 0:
+		return u
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Lo: u.Hi >> (n - 64)}
+	}
+	return Uint128{Hi: u.Hi >> n, Lo: u.Lo>>n | u.Hi<<(64-n)}
+}
+
+func (u Uint128) or(v Uint128) Uint128 { return Uint128{Hi: u.Hi | v.Hi, Lo: u.Lo | v.Lo} }
+
+func (u Uint128) and(v Uint128) Uint128 { return Uint128{Hi: u.Hi & v.Hi, Lo: u.Lo & v.Lo} }
+
+// mask64 returns the low n bits set, for n in [0, 64].
+func mask64(n uint) uint64 {
+	if n >= 64 {
+		return math.MaxUint64
+	}
+	return uint64(1)<<n - 1
+}
+
+// mask128 returns the low n bits set, for n in [0, 128].
+func mask128(n uint) Uint128 {
+	if n >= 64 {
+		return Uint128{Hi: mask64(n - 64), Lo: math.MaxUint64}
+	}
+	return Uint128{Lo: mask64(n)}
+}
+
+// Obfuscator128 defines a reversible permutation over a k-bit domain
+// [0, 2^k) with k up to 128.
+type Obfuscator128 interface {
+	DomainBits() uint
+	Obfuscate(x Uint128) Uint128
+	Deobfuscate(y Uint128) Uint128
+}
+
+// feistel128 is the 128-bit counterpart of feistel. Each half is at most 64
+// bits, so the rounds operate on uint64 and only packing needs Uint128.
+type feistel128 struct {
+	k      uint
+	rounds int
+	lBits  uint // left half bits (initial, low)
+	rBits  uint // right half bits (initial, high)
+	lMask  uint64
+	rMask  uint64
+	rk     []roundKey
+}
+
+// NewFeistel128 creates a k-bit Feistel obfuscator for wide IDs.
+// k must be in [2, 128], rounds >= 2.
+func NewFeistel128(k uint, rounds int) (Obfuscator128, error) {
+	if k < 2 || k > 128 {
+		return nil, fmt.Errorf("feistel128: k out of range: %d", k)
+	}
+	if rounds < 2 {
+		return nil, fmt.Errorf("feistel128: rounds must be >= 2")
+	}
+	l := (k + 1) / 2
+	r := k - l
+	f := &feistel128{
+		k:      k,
+		rounds: rounds,
+		lBits:  l,
+		rBits:  r,
+		lMask:  mask64(l),
+		rMask:  mask64(r),
+		rk:     make([]roundKey, rounds),
+	}
+	for i := range f.rk {
+		// Even rounds map R (rBits) -> lBits; odd rounds map lBits -> rBits.
+		inBits, outBits := r, l
+		if i%2 == 1 {
+			inBits, outBits = l, r
+		}
+		f.rk[i] = roundKey{
+			c:       roundConstants[i%len(roundConstants)] & mask64(inBits),
+			inMask:  mask64(inBits),
+			outMask: mask64(outBits),
+			outBits: outBits,
+		}
+	}
+	return f, nil
+}
+
+func (f *feistel128) DomainBits() uint { return f.k }
+
+// round maps an input to round i's output width with a splitmix64 finalizer;
+// the single multiply in simpleFbits mixes poorly across 64-bit halves.
+func (f *feistel128) round(input uint64, i int) uint64 {
+	rk := &f.rk[i]
+	x := (input&rk.inMask ^ rk.c) + uint64(i+1)*0x9E3779B97F4A7C15
+	x = (x ^ x>>30) * 0xBF58476D1CE4E5B9
+	x = (x ^ x>>27) * 0x94D049BB133111EB
+	x ^= x >> 31
+	return x & rk.outMask
+}
+
+func (f *feistel128) Obfuscate(x Uint128) Uint128 {
+	L := x.Lo & f.lMask
+	R := x.rsh(f.lBits).Lo & f.rMask
+	for i := 0; i < f.rounds; i++ {
+		fn := f.round(R, i)
+		if i%2 == 0 {
+			L, R = R, (L^fn)&f.lMask
+		} else {
+			L, R = R, (L^fn)&f.rMask
+		}
+	}
+	if f.rounds%2 == 0 {
+		return Uint128{Lo: R}.lsh(f.lBits).or(Uint128{Lo: L})
+	}
+	return Uint128{Lo: R}.lsh(f.rBits).or(Uint128{Lo: L})
+}
+
+func (f *feistel128) Deobfuscate(y Uint128) Uint128 {
+	var L, R uint64
+	if f.rounds%2 == 0 {
+		L = y.Lo & f.lMask
+		R = y.rsh(f.lBits).Lo & f.rMask
+	} else {
+		L = y.Lo & f.rMask
+		R = y.rsh(f.rBits).Lo & f.lMask
+	}
+	for i := f.rounds - 1; i >= 0; i-- {
+		fn := f.round(L, i)
+		if i%2 == 0 {
+			L, R = (R^fn)&f.lMask, L
+		} else {
+			L, R = (R^fn)&f.rMask, L
+		}
+	}
+	return Uint128{Lo: R}.lsh(f.lBits).or(Uint128{Lo: L})
+}
+
+// Describe implements Describer.
+func (f *feistel128) Describe() string {
+	return fmt.Sprintf("feistel128(k=%d,rounds=%d)", f.k, f.rounds)
+}
+
+// Generator128 produces wide IDs of up to 128 bits: a paced tick in the high
+// bits followed by random entropy, similar to ULID, obfuscated and encoded in
+// fixed-width base36 with the same dash grouping as Generator.
+type Generator128 struct {
+	epochMS  int64
+	pace     time.Duration
+	bits     uint
+	width    int
+	randBits uint // low bits filled from entropy
+	rounds   int
+	ob       Obfuscator128
+	entropy  io.Reader
+
+	randSet     bool
+	horizonTick int64
+
+	lastTick atomic.Int64
+}
+
+// Option128 configures a Generator128.
+type Option128 func(*Generator128) error
+
+// WithEpoch128 sets the epoch for wide ticks.
+func WithEpoch128(t time.Time) Option128 {
+	return func(g *Generator128) error {
+		g.epochMS = t.UTC().UnixMilli()
+		return nil
+	}
+}
+
+// WithPace128 sets the minimum spacing between wide ticks.
+func WithPace128(d time.Duration) Option128 {
+	return func(g *Generator128) error {
+		if d <= 0 {
+			return errors.New("pace must be > 0")
+		}
+		g.pace = d
+		return nil
+	}
+}
+
+// WithWidth128 sets the fixed base36 width of wide IDs, at most 25.
+func WithWidth128(width int) Option128 {
+	return func(g *Generator128) error {
+		if width < 1 || width > 25 {
+			return errors.New("width must be in [1,25]")
+		}
+		g.width = width
+		return nil
+	}
+}
+
+// WithBits128 sets the wide domain size in bits.
+func WithBits128(bits uint) Option128 {
+	return func(g *Generator128) error {
+		if bits < 2 || bits > 128 {
+			return errors.New("bits must be in [2,128]")
+		}
+		g.bits = bits
+		return nil
+	}
+}
+
+// WithEntropy128 sets how many of the low bits are random. The remaining high
+// bits, at most 63, hold the tick.
+func WithEntropy128(n uint) Option128 {
+	return func(g *Generator128) error {
+		if n > 127 {
+			return errors.New("entropy bits must be in [0,127]")
+		}
+		g.randBits = n
+		g.randSet = true
+		return nil
+	}
+}
+
+// WithEntropySource128 replaces crypto/rand as the source of random bits.
+func WithEntropySource128(r io.Reader) Option128 {
+	return func(g *Generator128) error {
+		if r == nil {
+			return errors.New("entropy source cannot be nil")
+		}
+		g.entropy = r
+		return nil
+	}
+}
+
+// WithObfuscation128 sets a custom wide obfuscation permutation.
+func WithObfuscation128(ob Obfuscator128) Option128 {
+	return func(g *Generator128) error {
+		if ob == nil {
+			return errors.New("obfuscator cannot be nil")
+		}
+		g.ob = ob
+		return nil
+	}
+}
+
+// New128 creates a wide generator. Defaults: epoch 2025-01-01 UTC, pace 1ms,
+// width 25, bits derived from width (128 for width 25), a 48-bit tick as in
+// ULID with the remaining bits random from crypto/rand, and Feistel128(bits, 4).
+func New128(opts ...Option128) (*Generator128, error) {
+	g := &Generator128{
+		epochMS: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		pace:    time.Millisecond,
+		width:   25,
+		rounds:  4,
+		entropy: rand.Reader,
+	}
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+	if g.bits == 0 {
+		g.bits = min(uint(math.Floor(float64(g.width)*5.16992500144)), 128)
+		if g.bits < 2 {
+			g.bits = 2
+		}
+	}
+	if !widthSupportsBits(g.width, g.bits) {
+		return nil, errors.New("width too small for selected bits")
+	}
+	if !g.randSet && g.bits > 48 {
+		g.randBits = g.bits - 48
+	}
+	if g.randBits >= g.bits {
+		return nil, errors.New("entropy bits must leave room for the tick")
+	}
+	tickBits := g.bits - g.randBits
+	if tickBits > 63 {
+		return nil, fmt.Errorf("tick needs %d bits (max 63); add entropy bits", tickBits)
+	}
+	if g.ob == nil {
+		ob, err := NewFeistel128(g.bits, g.rounds)
+		if err != nil {
+			return nil, err
+		}
+		g.ob = ob
+	} else if g.ob.DomainBits() != g.bits {
+		return nil, errors.New("obfuscator domain bits mismatch")
+	}
+	g.horizonTick = int64(mask64(tickBits))
+	return g, nil
+}
+
+// Generate returns a raw wide value: the next paced tick in the high bits and
+// fresh random bits below it. Ticks are strictly increasing as with
+// Generator.Generate. It fails only if the entropy source fails.
+func (g *Generator128) Generate() (Uint128, error) {
+	d := int64(g.pace / time.Millisecond)
+	if d <= 0 {
+		d = 1
+	}
+	var tick int64
+	for {
+		nowTick := (time.Now().UTC().UnixMilli() - g.epochMS) / d
+		last := g.lastTick.Load()
+		if nowTick > last {
+			if g.lastTick.CompareAndSwap(last, nowTick) {
+				tick = nowTick
+				break
+			}
+			continue
+		}
+		time.Sleep(g.pace)
+	}
+	raw := Uint128{Lo: uint64(tick)}.lsh(g.randBits)
+	if g.randBits == 0 {
+		return raw, nil
+	}
+	var buf [16]byte
+	if _, err := io.ReadFull(g.entropy, buf[:]); err != nil {
+		return Uint128{}, fmt.Errorf("read entropy: %w", err)
+	}
+	rnd := Uint128{Hi: binary.BigEndian.Uint64(buf[:8]), Lo: binary.BigEndian.Uint64(buf[8:])}
+	return raw.or(rnd.and(mask128(g.randBits))), nil
+}
+
+// Format converts a raw wide value into a fixed-width base36 string grouped
+// into chunks of 4 characters separated by '-'.
+func (g *Generator128) Format(raw Uint128) string {
+	v := g.ob.Obfuscate(raw.and(mask128(g.bits)))
+	var digits [25]byte // longest base36 uint128
+	i := len(digits)
+	for v != (Uint128{}) {
+		var r uint64
+		q := v.Hi / 36
+		v.Lo, r = bits.Div64(v.Hi%36, v.Lo, 36)
+		v.Hi = q
+		i--
+		digits[i] = alphabet36.digits[r]
+	}
+	d := digits[i:]
+	n := max(len(d), g.width)
+	pad := n - len(d)
+	var b strings.Builder
+	b.Grow(n + n/4)
+	for j := 0; j < n; j++ {
+		if j > 0 && j%4 == 0 {
+			b.WriteByte('-')
+		}
+		if j < pad {
+			b.WriteByte('0')
+		} else {
+			b.WriteByte(d[j-pad])
+		}
+	}
+	return b.String()
+}
+
+// Parse reverses Format and returns the raw wide value.
+func (g *Generator128) Parse(s string) (Uint128, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Uint128{}, errEmptyID
+	}
+	var v Uint128
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '-' {
+			continue
+		}
+		d := base36Digit[c]
+		if d == 0xff {
+			return Uint128{}, errInvalidID
+		}
+		hiHi, hi := bits.Mul64(v.Hi, 36)
+		carry, lo := bits.Mul64(v.Lo, 36)
+		lo, c1 := bits.Add64(lo, uint64(d), 0)
+		hi, c2 := bits.Add64(hi, carry, c1)
+		if hiHi != 0 || c2 != 0 {
+			return Uint128{}, errIDOverflow
+		}
+		v = Uint128{Hi: hi, Lo: lo}
+		digits++
+	}
+	if digits == 0 {
+		return Uint128{}, errInvalidID
+	}
+	if v.rsh(g.bits) != (Uint128{}) {
+		return Uint128{}, errIDOverflow
+	}
+	return g.ob.Deobfuscate(v), nil
+}
+
+// TimestampFromRaw converts a raw wide value to its UTC tick time, ignoring
+// the random bits.
+func (g *Generator128) TimestampFromRaw(raw Uint128) time.Time {
+	d := int64(g.pace / time.Millisecond)
+	if d <= 0 {
+		d = 1
+	}
+	tick := int64(raw.rsh(g.randBits).Lo)
+	return time.UnixMilli(g.epochMS + tick*d).UTC()
+}
+
+// TimestampFromID parses a formatted wide ID and returns the UTC timestamp.
+func (g *Generator128) TimestampFromID(id string) (time.Time, error) {
+	raw, err := g.Parse(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return g.TimestampFromRaw(raw), nil
+}
+
+// Horizon returns the UTC timestamp of the last tick that fits the tick bits.
+func (g *Generator128) Horizon() time.Time {
+	return g.TimestampFromRaw(Uint128{Lo: uint64(g.horizonTick)}.lsh(g.randBits))
+}
+
+// Width returns the formatted width in base36 characters.
+func (g *Generator128) Width() int { return g.width }
+
+// Bits returns the wide domain size in bits.
+func (g *Generator128) Bits() uint { return g.bits }
+
+// EntropyBits returns how many low bits of each raw value are random.
+func (g *Generator128) EntropyBits() uint { return g.randBits }
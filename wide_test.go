@@ -0,0 +1,115 @@
+package idgen
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeistel128Bijection(t *testing.T) {
+	// Exhaustive on a small domain (both round parities).
+	for _, rounds := range []int{3, 4} {
+		ob, err := NewFeistel128(12, rounds)
+		if err != nil {
+			t.Fatalf("NewFeistel128 error: %v", err)
+		}
+		seen := make(map[Uint128]bool)
+		for x := uint64(0); x < 1<<12; x++ {
+			y := ob.Obfuscate(Uint128{Lo: x})
+			if y.Hi != 0 || y.Lo >= 1<<12 || seen[y] {
+				t.Fatalf("rounds=%d: bad image %v for %d", rounds, y, x)
+			}
+			seen[y] = true
+			if back := ob.Deobfuscate(y); back != (Uint128{Lo: x}) {
+				t.Fatalf("rounds=%d: round-trip %d -> %v", rounds, x, back)
+			}
+		}
+	}
+	// Sampled round-trips on wide domains.
+	r := rand.New(rand.NewSource(1))
+	for _, k := range []uint{64, 65, 100, 127, 128} {
+		ob, err := NewFeistel128(k, 4)
+		if err != nil {
+			t.Fatalf("NewFeistel128(%d) error: %v", k, err)
+		}
+		for i := 0; i < 1000; i++ {
+			x := Uint128{Hi: r.Uint64(), Lo: r.Uint64()}.and(mask128(k))
+			y := ob.Obfuscate(x)
+			if y.rsh(k) != (Uint128{}) {
+				t.Fatalf("k=%d: image %v out of domain", k, y)
+			}
+			if back := ob.Deobfuscate(y); back != x {
+				t.Fatalf("k=%d: round-trip %v -> %v", k, x, back)
+			}
+		}
+	}
+}
+
+func TestGenerator128(t *testing.T) {
+	g, err := New128()
+	if err != nil {
+		t.Fatalf("New128 error: %v", err)
+	}
+	if g.Bits() != 128 || g.EntropyBits() != 80 || g.Width() != 25 {
+		t.Fatalf("defaults = %d bits, %d entropy, width %d", g.Bits(), g.EntropyBits(), g.Width())
+	}
+	before := time.Now().UTC().Add(-time.Millisecond)
+	raw, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	id := g.Format(raw)
+	if len(strings.ReplaceAll(id, "-", "")) != 25 {
+		t.Fatalf("Format = %q, want 25 characters", id)
+	}
+	back, err := g.Parse(strings.ToUpper(id))
+	if err != nil || back != raw {
+		t.Fatalf("Parse(%q) = %v, %v; want %v", id, back, err, raw)
+	}
+	ts, err := g.TimestampFromID(id)
+	if err != nil || ts.Before(before) || ts.After(time.Now().UTC()) {
+		t.Fatalf("TimestampFromID = %v, %v; want about now", ts, err)
+	}
+
+	// Entropy comes from the configured reader.
+	zero, err := New128(WithEntropySource128(bytes.NewReader(make([]byte, 16))))
+	if err != nil {
+		t.Fatalf("New128 error: %v", err)
+	}
+	raw, _ = zero.Generate()
+	if raw.and(mask128(80)) != (Uint128{}) {
+		t.Fatalf("expected zero entropy bits, got %v", raw)
+	}
+	if _, err := zero.Generate(); err == nil {
+		t.Fatal("expected error from exhausted entropy source")
+	}
+
+	if _, err := g.Parse("zzzzz-zzzzz-zzzzz-zzzzz-zzzzz-z"); err == nil {
+		t.Fatal("expected overflow error")
+	}
+}
+
+func TestGenerator128Validation(t *testing.T) {
+	cases := map[string][]Option128{
+		"width":       {WithWidth128(26)},
+		"bits":        {WithBits128(129)},
+		"small width": {WithWidth128(10), WithBits128(100)},
+		"wide tick":   {WithBits128(100), WithEntropy128(20)},
+		"no tick":     {WithBits128(64), WithEntropy128(64)},
+	}
+	for name, opts := range cases {
+		if _, err := New128(opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	g, err := New128(WithWidth128(12), WithBits128(60), WithEntropy128(0))
+	if err != nil {
+		t.Fatalf("New128 error: %v", err)
+	}
+	raw, _ := g.Generate()
+	if got, _ := g.Parse(g.Format(raw)); got != raw {
+		t.Fatalf("round-trip = %v, want %v", got, raw)
+	}
+}